@@ -5,13 +5,34 @@ import (
 )
 
 type cloudflareConfig struct {
-	Token             *string `hcl:"token"`
-	Email             *string `hcl:"email"`
-	APIKey            *string `hcl:"api_key"`
-	AccessKey         *string `hcl:"access_key"`
-	SecretKey         *string `hcl:"secret_key"`
-	MaxRetries        *int    `hcl:"max_retries"`
-	MaxRequestTimeout *int    `hcl:"max_request_timeout"`
+	Token             *string  `hcl:"token"`
+	Email             *string  `hcl:"email"`
+	APIKey            *string  `hcl:"api_key"`
+	AccessKey         *string  `hcl:"access_key"`
+	SecretKey         *string  `hcl:"secret_key"`
+	MaxRetries        *int     `hcl:"max_retries"`
+	MaxRequestTimeout *int     `hcl:"max_request_timeout"`
+	BaseURL           *string  `hcl:"base_url"`
+	R2Endpoint        *string  `hcl:"r2_endpoint"`
+	RequestsPerSecond *float64 `hcl:"requests_per_second"`
+	IgnoreErrorCodes  []string `hcl:"ignore_error_codes,optional"`
+	MaxObjectDataSize *int64   `hcl:"max_object_data_size"`
+
+	// Scoping filters, each entry is an ID or a name glob (e.g. "*.example.com")
+	Accounts     []string `hcl:"accounts,optional"`
+	Zones        []string `hcl:"zones,optional"`
+	ExcludeZones []string `hcl:"exclude_zones,optional"`
+
+	// R2 S3 API credentials for individual accounts, used in place of
+	// access_key and secret_key
+	R2Credentials []r2CredentialsConfig `hcl:"r2_credentials,block"`
+}
+
+type r2CredentialsConfig struct {
+	AccountID    string  `hcl:"account_id,label"`
+	AccessKey    *string `hcl:"access_key"`
+	SecretKey    *string `hcl:"secret_key"`
+	Jurisdiction *string `hcl:"jurisdiction"`
 }
 
 func ConfigInstance() interface{} {
@@ -0,0 +1,266 @@
+package cloudflare
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// spfLookupLimit is the most DNS lookups an SPF check may make before it
+// fails with a permerror (RFC 7208 section 4.6.4).
+const spfLookupLimit = 10
+
+// spfQualifiers names the result each SPF qualifier gives on a match.
+var spfQualifiers = map[byte]string{'+': "pass", '-': "fail", '~': "softfail", '?': "neutral"}
+
+// spfTerm is a mechanism, such as include:_spf.example.com, or a modifier,
+// such as redirect=example.com, of an SPF record.
+type spfTerm struct {
+	Qualifier string `json:"qualifier,omitempty"`
+	Mechanism string `json:"mechanism"`
+	Value     string `json:"value,omitempty"`
+}
+
+// spfRecord is a parsed SPF record.
+type spfRecord struct {
+	Terms        []spfTerm
+	AllQualifier string
+	Redirect     string
+	LookupCount  int
+}
+
+// dmarcRecord is a parsed DMARC record.
+type dmarcRecord struct {
+	Policy          string
+	SubdomainPolicy string
+	Pct             *int
+	Rua             []string
+	Ruf             []string
+	Tags            map[string]string
+}
+
+// dkimRecord is a DKIM public key published under a selector.
+type dkimRecord struct {
+	Selector string `json:"selector"`
+	Name     string `json:"name"`
+	KeyType  string `json:"key_type"`
+	Revoked  bool   `json:"revoked"`
+	Testing  bool   `json:"testing"`
+}
+
+// txtRecordValue joins the character strings of a TXT record's content into
+// the single value mail servers read. Content that is not quoted is returned
+// as is.
+func txtRecordValue(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, `"`) {
+		return content
+	}
+
+	var value strings.Builder
+	inQuote := false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote && c == '\\' && i+1 < len(content):
+			i++
+			value.WriteByte(content[i])
+		case inQuote:
+			value.WriteByte(c)
+		}
+	}
+	return value.String()
+}
+
+// hasRecordVersion reports whether a TXT value starts with the version tag,
+// e.g. "v=spf1", that identifies its kind of record.
+func hasRecordVersion(value string, version string) bool {
+	if len(value) < len(version) || !strings.EqualFold(value[:len(version)], version) {
+		return false
+	}
+	rest := value[len(version):]
+	return rest == "" || rest[0] == ' ' || rest[0] == ';'
+}
+
+// parseSPFRecord parses an SPF record, counting the terms that make DNS
+// lookups. Lookups made by included records are not counted, since they
+// would need further queries.
+func parseSPFRecord(value string) spfRecord {
+	var record spfRecord
+	fields := strings.Fields(value)
+	if len(fields) > 0 {
+		fields = fields[1:]
+	}
+
+	for _, field := range fields {
+		// Modifiers are name=value, mechanisms name, name:value or name/cidr
+		if i := strings.IndexAny(field, "=:/"); i > 0 && field[i] == '=' {
+			term := spfTerm{Mechanism: strings.ToLower(field[:i]), Value: field[i+1:]}
+			if term.Mechanism == "redirect" {
+				record.Redirect = term.Value
+				record.LookupCount++
+			}
+			record.Terms = append(record.Terms, term)
+			continue
+		}
+
+		term := spfTerm{Qualifier: "pass"}
+		if qualifier, ok := spfQualifiers[field[0]]; ok {
+			term.Qualifier = qualifier
+			field = field[1:]
+		}
+		term.Mechanism = strings.ToLower(field)
+		if i := strings.IndexAny(field, ":/"); i >= 0 {
+			term.Mechanism = strings.ToLower(field[:i])
+			term.Value = strings.TrimPrefix(field[i:], ":")
+		}
+
+		switch term.Mechanism {
+		case "include", "a", "mx", "ptr", "exists":
+			record.LookupCount++
+		case "all":
+			if record.AllQualifier == "" {
+				record.AllQualifier = term.Qualifier
+			}
+		}
+		record.Terms = append(record.Terms, term)
+	}
+
+	return record
+}
+
+// parseTagList parses the semicolon separated tag=value pairs used by DMARC,
+// DKIM and MTA-STS records. Tag names are returned in lower case.
+func parseTagList(value string) map[string]string {
+	tags := map[string]string{}
+	for _, pair := range strings.Split(value, ";") {
+		name, tagValue, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(tagValue)
+	}
+	return tags
+}
+
+// parseDMARCRecord parses a DMARC record. Pct is nil when the record has no
+// pct tag, which means the policy applies to all mail. The subdomain policy
+// defaults to the domain's own policy.
+func parseDMARCRecord(value string) dmarcRecord {
+	tags := parseTagList(value)
+	record := dmarcRecord{
+		Policy:          strings.ToLower(tags["p"]),
+		SubdomainPolicy: strings.ToLower(tags["sp"]),
+		Rua:             splitDMARCURIs(tags["rua"]),
+		Ruf:             splitDMARCURIs(tags["ruf"]),
+		Tags:            tags,
+	}
+	if record.SubdomainPolicy == "" {
+		record.SubdomainPolicy = record.Policy
+	}
+	if pct, err := strconv.Atoi(tags["pct"]); err == nil {
+		record.Pct = &pct
+	}
+	return record
+}
+
+func splitDMARCURIs(value string) []string {
+	var uris []string
+	for _, uri := range strings.Split(value, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// parseDKIMRecord parses the DKIM public key record of a selector. An empty
+// p tag means the key has been revoked.
+func parseDKIMRecord(selector string, name string, value string) dkimRecord {
+	tags := parseTagList(value)
+	record := dkimRecord{
+		Selector: selector,
+		Name:     name,
+		KeyType:  strings.ToLower(tags["k"]),
+		Revoked:  tags["p"] == "",
+	}
+	if record.KeyType == "" {
+		record.KeyType = "rsa"
+	}
+	for _, flag := range strings.Split(tags["t"], ":") {
+		if strings.TrimSpace(flag) == "y" {
+			record.Testing = true
+		}
+	}
+	return record
+}
+
+// emailSecurityFindings lists the problems with a zone's email
+// authentication records.
+func emailSecurityFindings(spfCount int, spf spfRecord, dmarcCount int, dmarc dmarcRecord, dkim []dkimRecord, mtaSTSCount int) []string {
+	findings := []string{}
+
+	switch {
+	case spfCount == 0:
+		findings = append(findings, "No SPF record")
+	case spfCount > 1:
+		findings = append(findings, fmt.Sprintf("%d SPF records, which makes SPF checks fail", spfCount))
+	}
+	if spfCount > 0 {
+		if spf.LookupCount > spfLookupLimit {
+			findings = append(findings, fmt.Sprintf("SPF record makes %d DNS lookups, more than the limit of %d", spf.LookupCount, spfLookupLimit))
+		}
+		switch {
+		case spf.AllQualifier == "pass":
+			findings = append(findings, "SPF record ends with +all, which lets any server send mail")
+		case spf.AllQualifier == "" && spf.Redirect == "":
+			findings = append(findings, "SPF record has no all mechanism or redirect")
+		}
+	}
+
+	switch {
+	case dmarcCount == 0:
+		findings = append(findings, "No DMARC record")
+	case dmarcCount > 1:
+		findings = append(findings, fmt.Sprintf("%d DMARC records, which makes DMARC checks fail", dmarcCount))
+	}
+	if dmarcCount > 0 {
+		switch dmarc.Policy {
+		case "none":
+			findings = append(findings, "DMARC policy is p=none, which only monitors mail")
+		case "quarantine", "reject":
+		default:
+			findings = append(findings, fmt.Sprintf("DMARC policy %q is not valid", dmarc.Policy))
+		}
+		if dmarc.SubdomainPolicy == "none" && dmarc.Policy != "none" {
+			findings = append(findings, "DMARC subdomain policy is sp=none")
+		}
+		switch {
+		case dmarc.Pct == nil:
+		case *dmarc.Pct <= 0:
+			findings = append(findings, "DMARC record has pct=0, so the policy is not applied to any mail")
+		case *dmarc.Pct < 100:
+			findings = append(findings, fmt.Sprintf("DMARC policy only applies to %d%% of mail", *dmarc.Pct))
+		}
+		if len(dmarc.Rua) == 0 {
+			findings = append(findings, "DMARC record has no rua address for aggregate reports")
+		}
+	}
+
+	for _, key := range dkim {
+		if key.Revoked {
+			findings = append(findings, fmt.Sprintf("DKIM key for selector %s is revoked", key.Selector))
+		}
+		if key.Testing {
+			findings = append(findings, fmt.Sprintf("DKIM key for selector %s is in testing mode", key.Selector))
+		}
+	}
+
+	if mtaSTSCount > 1 {
+		findings = append(findings, fmt.Sprintf("%d MTA-STS records, which makes MTA-STS checks fail", mtaSTSCount))
+	}
+
+	return findings
+}
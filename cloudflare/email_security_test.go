@@ -0,0 +1,93 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTXTRecordValue(t *testing.T) {
+	tests := []struct {
+		content, want string
+	}{
+		{`"v=spf1 -all"`, "v=spf1 -all"},
+		{`"v=spf1 include:a.example.net " "-all"`, "v=spf1 include:a.example.net -all"},
+		{`"say \"hi\""`, `say "hi"`},
+		{"v=spf1 -all", "v=spf1 -all"},
+	}
+	for _, tt := range tests {
+		if got := txtRecordValue(tt.content); got != tt.want {
+			t.Errorf("txtRecordValue(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestHasRecordVersion(t *testing.T) {
+	tests := []struct {
+		value, version string
+		want           bool
+	}{
+		{"v=spf1 -all", "v=spf1", true},
+		{"V=SPF1", "v=spf1", true},
+		{"v=spf10 -all", "v=spf1", false},
+		{"v=DMARC1;p=none", "v=DMARC1", true},
+		{"v=DMARC1 ; p=none", "v=DMARC1", true},
+	}
+	for _, tt := range tests {
+		if got := hasRecordVersion(tt.value, tt.version); got != tt.want {
+			t.Errorf("hasRecordVersion(%q, %q) = %v, want %v", tt.value, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseSPFRecord(t *testing.T) {
+	record := parseSPFRecord("v=spf1 a/24 mx:mail.example.com ~ip6:2001:db8::/32 ?exists:%{i}.example.net exp=explain.example.com ~all")
+
+	want := spfRecord{
+		Terms: []spfTerm{
+			{Qualifier: "pass", Mechanism: "a", Value: "/24"},
+			{Qualifier: "pass", Mechanism: "mx", Value: "mail.example.com"},
+			{Qualifier: "softfail", Mechanism: "ip6", Value: "2001:db8::/32"},
+			{Qualifier: "neutral", Mechanism: "exists", Value: "%{i}.example.net"},
+			{Mechanism: "exp", Value: "explain.example.com"},
+			{Qualifier: "softfail", Mechanism: "all"},
+		},
+		AllQualifier: "softfail",
+		LookupCount:  3,
+	}
+	gotJSON, _ := json.Marshal(record)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("parseSPFRecord() = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestParseDMARCRecord(t *testing.T) {
+	record := parseDMARCRecord("v=DMARC1; p=Quarantine; pct=25; rua=mailto:a@example.com, mailto:b@example.com")
+
+	assertValue(t, "policy", record.Policy, "quarantine")
+	assertValue(t, "subdomain policy", record.SubdomainPolicy, "quarantine")
+	assertValue(t, "pct", *record.Pct, 25)
+	assertValue(t, "rua", record.Rua, []string{"mailto:a@example.com", "mailto:b@example.com"})
+	assertValue(t, "ruf", record.Ruf, nil)
+
+	record = parseDMARCRecord("v=DMARC1; p=reject; pct=0")
+	assertValue(t, "explicit pct=0", *record.Pct, 0)
+	record = parseDMARCRecord("v=DMARC1; p=reject")
+	if record.Pct != nil {
+		t.Errorf("pct = %d, want nil when the tag is absent", *record.Pct)
+	}
+}
+
+func TestEmailSecurityFindings(t *testing.T) {
+	findings := emailSecurityFindings(1, parseSPFRecord("v=spf1 include:example.net"), 0, dmarcRecord{}, nil, 2)
+
+	assertValue(t, "findings", findings, []string{
+		"SPF record has no all mechanism or redirect",
+		"No DMARC record",
+		"2 MTA-STS records, which makes MTA-STS checks fail",
+	})
+	assertValue(t, "no findings", emailSecurityFindings(1, parseSPFRecord("v=spf1 -all"), 1, parseDMARCRecord("v=DMARC1; p=reject; rua=mailto:a@example.com"), nil, 0), []string{})
+	assertValue(t, "no SPF", emailSecurityFindings(0, spfRecord{}, 1, parseDMARCRecord("v=DMARC1; p=reject; sp=none; rua=mailto:a@example.com"), nil, 0), []string{"No SPF record", "DMARC subdomain policy is sp=none"})
+	assertValue(t, "+all", emailSecurityFindings(1, parseSPFRecord("v=spf1 +all"), 1, parseDMARCRecord("v=DMARC1; p=bogus; rua=mailto:a@example.com"), nil, 0), []string{"SPF record ends with +all, which lets any server send mail", `DMARC policy "bogus" is not valid`})
+	assertValue(t, "pct=0", emailSecurityFindings(1, parseSPFRecord("v=spf1 -all"), 1, parseDMARCRecord("v=DMARC1; p=reject; pct=0; rua=mailto:a@example.com"), nil, 0), []string{"DMARC record has pct=0, so the policy is not applied to any mail"})
+}
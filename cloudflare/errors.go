@@ -0,0 +1,215 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/smithy-go"
+	cloudflare4 "github.com/cloudflare/cloudflare-go/v4"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+)
+
+// Cloudflare API error codes that carry more meaning than the HTTP status
+// they are returned with.
+// See https://developers.cloudflare.com/fundamentals/api/troubleshooting/
+const (
+	// Returned with a 403 when the token lacks a permission for the resource
+	errCodeUnauthorized = 9109
+	// Returned when the credentials are not accepted, e.g. a revoked token
+	errCodeAuthenticationError = 10000
+	// Returned with a 400 for a malformed object identifier in the URL
+	errCodeCouldNotRoute = 7003
+	// Returned with a 429 when the global API rate limit is exceeded
+	errCodeRateLimited = 971
+)
+
+// R2 S3 API error codes, grouped by how the plugin treats them
+var (
+	s3NotFoundErrorCodes   = []string{"NotFound", "NoSuchBucket", "NoSuchKey", "NoSuchUpload"}
+	s3ForbiddenErrorCodes  = []string{"AccessDenied", "Unauthorized"}
+	s3PermissionErrorCodes = []string{"AccessDenied"}
+	s3ThrottlingErrorCodes = []string{"SlowDown", "TooManyRequests"}
+)
+
+// cloudflareError unwraps a Cloudflare v4 API error
+func cloudflareError(err error) (*cloudflare4.Error, bool) {
+	var apiErr *cloudflare4.Error
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// hasCloudflareErrorCode reports whether err is a Cloudflare API error
+// carrying any of the given numeric error codes.
+func hasCloudflareErrorCode(err error, codes ...int64) bool {
+	apiErr, ok := cloudflareError(err)
+	if !ok {
+		return false
+	}
+	for _, e := range apiErr.Errors {
+		if slices.Contains(codes, e.Code) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasS3ErrorCode reports whether err is an R2 S3 API error with any of the
+// given error codes.
+func hasS3ErrorCode(err error, codes ...string) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return slices.Contains(codes, apiErr.ErrorCode())
+}
+
+// errorStatusCode returns the HTTP status code of a Cloudflare or R2 API
+// error, or 0 if err did not come from an API response.
+func errorStatusCode(err error) int {
+	if apiErr, ok := cloudflareError(err); ok {
+		return apiErr.StatusCode
+	}
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode()
+	}
+	return 0
+}
+
+// isNotFoundError reports whether err means the requested resource does not
+// exist, e.g. a get with an unknown ID.
+func isNotFoundError(err error) bool {
+	return errorStatusCode(err) == http.StatusNotFound ||
+		hasCloudflareErrorCode(err, errCodeCouldNotRoute) ||
+		hasS3ErrorCode(err, s3NotFoundErrorCodes...)
+}
+
+// isForbiddenError reports whether err means the credentials may not access
+// the resource, either for lack of a permission or because the feature is not
+// available on the account's plan.
+func isForbiddenError(err error) bool {
+	status := errorStatusCode(err)
+	return status == http.StatusForbidden || status == http.StatusUnauthorized ||
+		hasCloudflareErrorCode(err, errCodeUnauthorized, errCodeAuthenticationError) ||
+		hasS3ErrorCode(err, s3ForbiddenErrorCodes...)
+}
+
+// isPermissionError reports whether err means the credentials lack a
+// permission for the resource. Unlike isForbiddenError it does not match
+// authentication errors, which a revoked or invalid token gets on every call.
+func isPermissionError(err error) bool {
+	return hasCloudflareErrorCode(err, errCodeUnauthorized) ||
+		hasS3ErrorCode(err, s3PermissionErrorCodes...)
+}
+
+// isThrottlingError reports whether err is a rate limit response that is
+// worth retrying once the client's own retries have been exhausted.
+func isThrottlingError(err error) bool {
+	return errorStatusCode(err) == http.StatusTooManyRequests ||
+		hasCloudflareErrorCode(err, errCodeRateLimited) ||
+		hasS3ErrorCode(err, s3ThrottlingErrorCodes...)
+}
+
+// errorCodes returns the codes identifying err that ignore_error_codes
+// entries are matched against: the HTTP status code, any Cloudflare numeric
+// error codes and the R2 S3 error code.
+func errorCodes(err error) []string {
+	var codes []string
+	if status := errorStatusCode(err); status != 0 {
+		codes = append(codes, strconv.Itoa(status))
+	}
+	if apiErr, ok := cloudflareError(err); ok {
+		for _, e := range apiErr.Errors {
+			codes = append(codes, strconv.FormatInt(e.Code, 10))
+		}
+	}
+	var s3Err smithy.APIError
+	if errors.As(err, &s3Err) && s3Err.ErrorCode() != "" {
+		codes = append(codes, s3Err.ErrorCode())
+	}
+	return codes
+}
+
+// matchesErrorCodes reports whether any of err's codes match the patterns,
+// each of which is a code or a glob such as "4*". Matching is
+// case-insensitive.
+func matchesErrorCodes(patterns []string, err error) bool {
+	codes := errorCodes(err)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		for _, code := range codes {
+			if ok, _ := path.Match(pattern, strings.ToLower(code)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shouldIgnoreErrorPluginDefault is the plugin's default ignore predicate. It
+// ignores errors matching the connection's ignore_error_codes, and permission
+// errors raised while fetching data for a single account, zone or row (i.e.
+// when there is a parent or row item), so that a token scoped to some zones
+// does not fail the whole query. Authentication errors are not ignored, so a
+// revoked token fails the query rather than returning no rows.
+func shouldIgnoreErrorPluginDefault() plugin.ErrorPredicateWithContext {
+	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
+		if matchesErrorCodes(GetConfig(d.Connection).IgnoreErrorCodes, err) {
+			return true
+		}
+		if h != nil && h.Item != nil && isPermissionError(err) {
+			plugin.Logger(ctx).Warn("shouldIgnoreErrorPluginDefault", "table", d.Table.Name, "ignoring permission error", err)
+			return true
+		}
+		return false
+	}
+}
+
+// shouldIgnoreErrors returns an ignore predicate for a table's get or hydrate
+// config. It ignores errors matching any of the given predicates in addition
+// to those ignored by shouldIgnoreErrorPluginDefault, which a table level
+// ignore config would otherwise replace.
+func shouldIgnoreErrors(predicates ...plugin.ErrorPredicate) plugin.ErrorPredicateWithContext {
+	pluginDefault := shouldIgnoreErrorPluginDefault()
+	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
+		for _, predicate := range predicates {
+			if predicate(err) {
+				return true
+			}
+		}
+		return pluginDefault(ctx, d, h, err)
+	}
+}
+
+// ignoreChildListErrors wraps a child list function so that errors ignored by
+// shouldIgnoreErrorPluginDefault end the listing for that parent item only.
+func ignoreChildListErrors(list plugin.HydrateFunc) plugin.HydrateFunc {
+	shouldIgnore := shouldIgnoreErrorPluginDefault()
+	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+		item, err := list(ctx, d, h)
+		if err != nil && shouldIgnore(ctx, d, h, err) {
+			return nil, nil
+		}
+		return item, err
+	}
+}
+
+// shouldRetryError retries rate limited calls. The API clients already retry
+// these with backoff, so this only comes into play when a burst of concurrent
+// queries outlasts max_retries.
+func shouldRetryError() plugin.ErrorPredicateWithContext {
+	return func(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, err error) bool {
+		if isThrottlingError(err) {
+			plugin.Logger(ctx).Debug("shouldRetryError", "table", d.Table.Name, "retrying throttled request", err)
+			return true
+		}
+		return false
+	}
+}
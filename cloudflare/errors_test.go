@@ -0,0 +1,85 @@
+package cloudflare
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	cloudflare4 "github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/shared"
+)
+
+func apiError(status int, codes ...int64) error {
+	err := &cloudflare4.Error{
+		StatusCode: status,
+		Request:    httptest.NewRequest("GET", "/client/v4/zones", nil),
+		Response:   &http.Response{StatusCode: status},
+	}
+	for _, code := range codes {
+		err.Errors = append(err.Errors, shared.ErrorData{Code: code})
+	}
+	// Errors reach the plugin wrapped by the paginators and hydrate calls
+	return fmt.Errorf("listing: %w", err)
+}
+
+func TestErrorClassification(t *testing.T) {
+	cases := []struct {
+		name                                      string
+		err                                       error
+		notFound, forbidden, permission, throttle bool
+	}{
+		{"not found", apiError(404, 1001), true, false, false, false},
+		{"invalid identifier", apiError(400, errCodeCouldNotRoute), true, false, false, false},
+		{"authentication error", apiError(403, errCodeAuthenticationError), false, true, false, false},
+		{"invalid token", apiError(401), false, true, false, false},
+		{"missing permission", apiError(403, errCodeUnauthorized), false, true, true, false},
+		{"unauthorized code", apiError(400, errCodeUnauthorized), false, true, true, false},
+		{"rate limited", apiError(429, errCodeRateLimited), false, false, false, true},
+		{"server error", apiError(500), false, false, false, false},
+		{"s3 no such key", &smithy.GenericAPIError{Code: "NoSuchKey"}, true, false, false, false},
+		{"s3 access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, false, true, true, false},
+		{"s3 unauthorized", &smithy.GenericAPIError{Code: "Unauthorized"}, false, true, false, false},
+		{"s3 slow down", &smithy.GenericAPIError{Code: "SlowDown"}, false, false, false, true},
+		{"other", fmt.Errorf("remote error: tls: handshake failure"), false, false, false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isNotFoundError(c.err); got != c.notFound {
+				t.Errorf("isNotFoundError = %v, want %v", got, c.notFound)
+			}
+			if got := isForbiddenError(c.err); got != c.forbidden {
+				t.Errorf("isForbiddenError = %v, want %v", got, c.forbidden)
+			}
+			if got := isPermissionError(c.err); got != c.permission {
+				t.Errorf("isPermissionError = %v, want %v", got, c.permission)
+			}
+			if got := isThrottlingError(c.err); got != c.throttle {
+				t.Errorf("isThrottlingError = %v, want %v", got, c.throttle)
+			}
+		})
+	}
+}
+
+func TestMatchesErrorCodes(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		err      error
+		want     bool
+	}{
+		{[]string{"9109"}, apiError(403, 9109), true},
+		{[]string{"403"}, apiError(403, 9109), true},
+		{[]string{"4*"}, apiError(403, 9109), true},
+		{[]string{"10000", "404"}, apiError(403, 9109), false},
+		{[]string{"accessdenied"}, &smithy.GenericAPIError{Code: "AccessDenied"}, true},
+		{[]string{"NoSuch*"}, &smithy.GenericAPIError{Code: "NoSuchBucket"}, true},
+		{[]string{"*"}, fmt.Errorf("not an API error"), false},
+		{nil, apiError(403, 9109), false},
+	}
+	for _, c := range cases {
+		if got := matchesErrorCodes(c.patterns, c.err); got != c.want {
+			t.Errorf("matchesErrorCodes(%q, %v) = %v, want %v", c.patterns, c.err, got, c.want)
+		}
+	}
+}
@@ -2,44 +2,72 @@ package cloudflare
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cloudflare/cloudflare-go/v4/accounts"
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
 )
 
-const matrixKeyAccount = "account_id"
+const (
+	matrixKeyAccount = "account_id"
+	// Carries an error from building the matrix through to the list call
+	matrixKeyError = "matrix_error"
+)
 
 // BuildAccountmatrix :: return a list of matrix items, one per account.
 // Allows to perform three level resource listing as in case of cloudflare_access_policy
 // (i.e List Account -> List Applications -> List Access policies for each application)
 func BuildAccountmatrix(ctx context.Context, d *plugin.QueryData) []map[string]interface{} {
+	matrix, err := getAccountMatrix(ctx, d)
+	if err != nil {
+		// Matrix functions cannot return an error, so run the list once with
+		// the error for accountMatrixError to report
+		plugin.Logger(ctx).Error("BuildAccountmatrix", "error", err)
+		return []map[string]interface{}{{matrixKeyAccount: "", matrixKeyError: err}}
+	}
+	return matrix
+}
 
-	// cache matrix
-	cacheKey := "AccountListMatrix"
+// getAccountMatrix lists the accounts allowed by the connection's accounts
+// filter. Successful results are cached per connection.
+func getAccountMatrix(ctx context.Context, d *plugin.QueryData) ([]map[string]interface{}, error) {
+	cacheKey := fmt.Sprintf("account-matrix-%s", d.Connection.Name)
 	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
-		return cachedData.([]map[string]interface{})
+		return cachedData.([]map[string]interface{}), nil
 	}
 
 	conn, err := connectV4(ctx, d)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
-	page, err := conn.Accounts.List(ctx, accounts.AccountListParams{})
-	if err != nil {
-		panic(err.Error())
-	}
-	matrix := make([]map[string]interface{}, len(page.Result))
-	for page != nil {
-		for i, account := range page.Result {
-			matrix[i] = map[string]interface{}{matrixKeyAccount: account.ID}
+	matrix := []map[string]interface{}{}
+	iter := conn.Accounts.ListAutoPaging(ctx, accounts.AccountListParams{})
+	for iter.Next() {
+		account := iter.Current()
+
+		// Skip accounts excluded by the connection's accounts option
+		inScope, err := accountInScope(d, account.ID, account.Name)
+		if err != nil {
+			return nil, err
 		}
-		if page, err = page.GetNextPage(); err != nil {
-			panic(err.Error())
+		if inScope {
+			matrix = append(matrix, map[string]interface{}{matrixKeyAccount: account.ID})
 		}
 	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
 
-	// set cache
 	d.ConnectionManager.Cache.Set(cacheKey, matrix)
-	return matrix
+	return matrix, nil
+}
+
+// accountMatrixError returns the error, if any, that BuildAccountmatrix hit
+// while building the matrix for this query.
+func accountMatrixError(ctx context.Context) error {
+	if err, ok := plugin.GetMatrixItem(ctx)[matrixKeyError].(error); ok {
+		return fmt.Errorf("listing accounts: %w", err)
+	}
+	return nil
 }
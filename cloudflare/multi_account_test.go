@@ -0,0 +1,64 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+const secondAccountID = "2b0e4c8f1d3a5e7c9b2d4f6a8c0e2a4b"
+
+// withSecondAccountPage serves a second page of accounts holding an account
+// with no Access applications.
+func withSecondAccountPage(h *replayHarness) {
+	h.server.handle(fixtureInteraction{
+		Path:  "/client/v4/accounts",
+		Query: map[string]string{"page": "2"},
+		Body:  []byte(`{"success":true,"errors":[],"messages":[],"result":[{"id":"` + secondAccountID + `","name":"Second Account","type":"standard"}],"result_info":{"page":2,"per_page":1,"count":1,"total_count":2}}`),
+	})
+	h.server.handle(fixtureInteraction{
+		Path: "/client/v4/accounts/" + secondAccountID + "/access/apps",
+		Body: []byte(`{"success":true,"errors":[],"messages":[],"result":[],"result_info":{"page":1,"per_page":20,"count":0,"total_count":0}}`),
+	})
+}
+
+func TestAccountMatrixPaginates(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_policy")
+	withSecondAccountPage(h)
+	rows := h.mustQuery("cloudflare_access_policy", []string{"id", "account_id"})
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d policies, want 2", len(rows))
+	}
+	// Page 1, page 2, then the empty page that ends iteration
+	if n := h.server.requestCount("GET", "/client/v4/accounts"); n != 3 {
+		t.Errorf("made %d account list calls, want 3", n)
+	}
+	if n := h.server.requestCount("GET", "/client/v4/accounts/"+secondAccountID+"/access/apps"); n != 1 {
+		t.Errorf("account on the second page was queried %d times, want 1", n)
+	}
+}
+
+func TestAccountMatrixScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `accounts = ["Second *"]`, "accounts", "access_policy")
+	withSecondAccountPage(h)
+	rows := h.mustQuery("cloudflare_access_policy", []string{"id"})
+
+	assertRows(t, rows, "id", nil)
+	if n := h.server.requestCount("GET", "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/access/apps"); n != 0 {
+		t.Errorf("excluded account should not be queried, made %d calls", n)
+	}
+}
+
+func TestAccountMatrixError(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_policy")
+	h.server.handle(fixtureInteraction{
+		Path:   "/client/v4/accounts",
+		Status: 400,
+		Body:   []byte(`{"success":false,"errors":[{"code":6003,"message":"Invalid request headers"}],"messages":[],"result":null}`),
+	})
+
+	_, err := h.query("cloudflare_access_policy", []string{"id"}, nil, 0)
+	if err == nil || !strings.Contains(err.Error(), "listing accounts") {
+		t.Errorf("got error %v, want the account listing error", err)
+	}
+}
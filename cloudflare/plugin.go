@@ -5,6 +5,7 @@ import (
 
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/rate_limiter"
 )
 
 func Plugin(ctx context.Context) *plugin.Plugin {
@@ -13,6 +14,9 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 		ConnectionConfigSchema: &plugin.ConnectionConfigSchema{
 			NewInstance: ConfigInstance,
 		},
+		// user_id is null for connections using an account-owned token. account_id
+		// is not a key column: the SDK applies key columns to every table and
+		// connection, and a user token's rows span many accounts.
 		ConnectionKeyColumns: []plugin.ConnectionKeyColumn{
 			{
 				Name:    "user_id",
@@ -20,37 +24,105 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			},
 		},
 		DefaultTransform: transform.FromCamel(),
+		DefaultIgnoreConfig: &plugin.IgnoreConfig{
+			ShouldIgnoreErrorFunc: shouldIgnoreErrorPluginDefault(),
+		},
+		DefaultRetryConfig: &plugin.RetryConfig{
+			ShouldRetryErrorFunc: shouldRetryError(),
+			MaxAttempts:          5,
+			BackoffAlgorithm:     "Exponential",
+			RetryInterval:        1000,
+			CappedDuration:       30000,
+		},
+		// Requests per second are throttled by the API client (see
+		// requests_per_second); these limiters bound how many hydrate calls run
+		// at once. Both can be overridden with limiter blocks in Steampipe config.
+		RateLimiters: []*rate_limiter.Definition{
+			{
+				// R2 data is served by the S3 API, which is not subject to the
+				// v4 API quota; those calls are tagged api = "s3"
+				Name:           "cloudflare_api",
+				MaxConcurrency: 25,
+				Scope:          []string{"connection"},
+				Where:          "api = 'v4'",
+			},
+			{
+				// Leaves room for the other tables of a join, as a table that
+				// fans out over zones, such as cloudflare_zone with its ten
+				// hydrated columns, could otherwise take every call
+				Name:           "cloudflare_api_table",
+				MaxConcurrency: 10,
+				Scope:          []string{"connection", "table"},
+				Where:          "api = 'v4'",
+			},
+		},
 		TableMap: map[string]*plugin.Table{
-			"cloudflare_access_application":    tableCloudflareAccessApplication(ctx),
-			"cloudflare_access_group":          tableCloudflareAccessGroup(ctx),
-			"cloudflare_access_policy":         tableCloudflareAccessPolicy(ctx),
-			"cloudflare_account":               tableCloudflareAccount(ctx),
-			"cloudflare_account_member":        tableCloudflareAccountMember(ctx),
-			"cloudflare_account_role":          tableCloudflareAccountRole(ctx),
-			"cloudflare_api_token":             tableCloudflareAPIToken(ctx),
-			"cloudflare_custom_certificate":    tableCloudflareCustomCertificate(ctx),
-			"cloudflare_custom_page":           tableCloudflareCustomPage(ctx),
-			"cloudflare_dns_record":            tableCloudflareDNSRecord(ctx),
-			"cloudflare_firewall_rule":         tableCloudflareFirewallRule(ctx),
-			"cloudflare_healthcheck":           tableCloudflareHealthcheck(ctx),
-			"cloudflare_load_balancer":         tableCloudflareLoadBalancer(ctx),
-			"cloudflare_load_balancer_monitor": tableCloudflareLoadBalancerMonitor(ctx),
-			"cloudflare_load_balancer_pool":    tableCloudflareLoadBalancerPool(ctx),
-			"cloudflare_logpush_job":           tableCloudflareLogpushJob(ctx),
-			"cloudflare_managed_transform":     tableCloudflareManagedTransform(ctx),
-			"cloudflare_notification_policy":   tableCloudflareNotificationPolicy(ctx),
-			"cloudflare_page_rule":             tableCloudflarePageRule(ctx),
-			"cloudflare_r2_bucket":             tableCloudflareR2Bucket(ctx),
-			"cloudflare_r2_object":             tableCloudflareR2Object(ctx),
-			"cloudflare_r2_object_data":        tableCloudflareR2ObjectData(ctx),
-			"cloudflare_ruleset":               tableCloudflareRuleset(ctx),
-			"cloudflare_user":                  tableCloudflareUser(ctx),
-			"cloudflare_user_audit_log":        tableCloudflareUserAuditLog(ctx),
-			"cloudflare_worker_route":          tableCloudflareWorkerRoute(ctx),
-			"cloudflare_worker_script":         tableCloudflareWorkerScript(ctx),
-			"cloudflare_zone":                  tableCloudflareZone(ctx),
-			"cloudflare_zone_setting":          tableCloudflareZoneSetting(ctx),
+			"cloudflare_access_application":     tableCloudflareAccessApplication(ctx),
+			"cloudflare_access_group":           tableCloudflareAccessGroup(ctx),
+			"cloudflare_access_policy":          tableCloudflareAccessPolicy(ctx),
+			"cloudflare_account":                tableCloudflareAccount(ctx),
+			"cloudflare_account_member":         tableCloudflareAccountMember(ctx),
+			"cloudflare_account_role":           tableCloudflareAccountRole(ctx),
+			"cloudflare_api_token":              tableCloudflareAPIToken(ctx),
+			"cloudflare_custom_certificate":     tableCloudflareCustomCertificate(ctx),
+			"cloudflare_custom_page":            tableCloudflareCustomPage(ctx),
+			"cloudflare_dns_dangling_record":    tableCloudflareDNSDanglingRecord(ctx),
+			"cloudflare_dns_email_security":     tableCloudflareDNSEmailSecurity(ctx),
+			"cloudflare_dns_firewall":           tableCloudflareDNSFirewall(ctx),
+			"cloudflare_dns_record":             tableCloudflareDNSRecord(ctx),
+			"cloudflare_dns_setting":            tableCloudflareDNSSetting(ctx),
+			"cloudflare_dns_zone_file":          tableCloudflareDNSZoneFile(ctx),
+			"cloudflare_firewall_rule":          tableCloudflareFirewallRule(ctx),
+			"cloudflare_healthcheck":            tableCloudflareHealthcheck(ctx),
+			"cloudflare_load_balancer":          tableCloudflareLoadBalancer(ctx),
+			"cloudflare_load_balancer_monitor":  tableCloudflareLoadBalancerMonitor(ctx),
+			"cloudflare_load_balancer_pool":     tableCloudflareLoadBalancerPool(ctx),
+			"cloudflare_logpush_job":            tableCloudflareLogpushJob(ctx),
+			"cloudflare_managed_transform":      tableCloudflareManagedTransform(ctx),
+			"cloudflare_notification_policy":    tableCloudflareNotificationPolicy(ctx),
+			"cloudflare_page_rule":              tableCloudflarePageRule(ctx),
+			"cloudflare_r2_bucket":              tableCloudflareR2Bucket(ctx),
+			"cloudflare_r2_bucket_usage":        tableCloudflareR2BucketUsage(ctx),
+			"cloudflare_r2_multipart_upload":    tableCloudflareR2MultipartUpload(ctx),
+			"cloudflare_r2_object":              tableCloudflareR2Object(ctx),
+			"cloudflare_r2_object_content":      tableCloudflareR2ObjectContent(ctx),
+			"cloudflare_r2_object_data":         tableCloudflareR2ObjectData(ctx),
+			"cloudflare_ruleset":                tableCloudflareRuleset(ctx),
+			"cloudflare_ruleset_rule":           tableCloudflareRulesetRule(ctx),
+			"cloudflare_secondary_dns_acl":      tableCloudflareSecondaryDNSACL(ctx),
+			"cloudflare_secondary_dns_incoming": tableCloudflareSecondaryDNSIncoming(ctx),
+			"cloudflare_secondary_dns_outgoing": tableCloudflareSecondaryDNSOutgoing(ctx),
+			"cloudflare_secondary_dns_peer":     tableCloudflareSecondaryDNSPeer(ctx),
+			"cloudflare_secondary_dns_tsig":     tableCloudflareSecondaryDNSTSIG(ctx),
+			"cloudflare_user":                   tableCloudflareUser(ctx),
+			"cloudflare_user_audit_log":         tableCloudflareUserAuditLog(ctx),
+			"cloudflare_worker_route":           tableCloudflareWorkerRoute(ctx),
+			"cloudflare_worker_script":          tableCloudflareWorkerScript(ctx),
+			"cloudflare_zone":                   tableCloudflareZone(ctx),
+			"cloudflare_zone_dnssec":            tableCloudflareZoneDNSSEC(ctx),
+			"cloudflare_zone_setting":           tableCloudflareZoneSetting(ctx),
 		},
 	}
+
+	// Calls are made to the v4 API unless the table or hydrate config is tagged
+	// with s3APITags
+	for _, table := range p.TableMap {
+		if table.Tags == nil {
+			table.Tags = map[string]string{}
+		}
+		if _, ok := table.Tags["api"]; !ok {
+			table.Tags["api"] = "v4"
+		}
+	}
+
+	// The SDK applies ignore configs to a list's parent hydrate but not to the
+	// child list call made for each parent item, so a permission error on a
+	// single zone or account would otherwise fail the whole query
+	for _, table := range p.TableMap {
+		if table.List != nil && table.List.ParentHydrate != nil {
+			table.List.Hydrate = ignoreChildListErrors(table.List.Hydrate)
+		}
+	}
+
 	return p
 }
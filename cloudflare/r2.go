@@ -0,0 +1,381 @@
+package cloudflare
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/klauspost/compress/zstd"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+)
+
+// R2 jurisdictions. Buckets in the EU and FedRAMP jurisdictions are only
+// reachable through the jurisdiction's own S3 endpoint.
+// See https://developers.cloudflare.com/r2/reference/data-location/#jurisdictional-restrictions
+const (
+	r2JurisdictionDefault = "default"
+	r2JurisdictionEU      = "eu"
+	r2JurisdictionFedRAMP = "fedramp"
+)
+
+var r2Jurisdictions = []string{r2JurisdictionDefault, r2JurisdictionEU, r2JurisdictionFedRAMP}
+
+// errR2CredentialsNotFound is returned when no S3 API keys are configured for
+// an account. Bucket metadata can still be read through the Cloudflare API.
+var errR2CredentialsNotFound = errors.New("cloudflare R2 API credentials not found. Edit your connection to configure AccessKey and Secret, and then restart Steampipe")
+
+// r2Credentials is an S3 API key pair for one account and jurisdiction
+type r2Credentials struct {
+	AccessKey    string
+	SecretKey    string
+	Jurisdiction string
+}
+
+// getR2Credentials returns the R2 credentials configured for an account, one
+// per jurisdiction. Accounts without an r2_credentials block fall back to the
+// connection's access_key and secret_key in the default jurisdiction.
+func getR2Credentials(config cloudflareConfig, accountID string) ([]r2Credentials, error) {
+	var creds []r2Credentials
+	for _, block := range config.R2Credentials {
+		if block.AccountID != accountID {
+			continue
+		}
+		c := r2Credentials{Jurisdiction: r2JurisdictionDefault}
+		if block.AccessKey != nil {
+			c.AccessKey = *block.AccessKey
+		}
+		if block.SecretKey != nil {
+			c.SecretKey = *block.SecretKey
+		}
+		if block.Jurisdiction != nil && *block.Jurisdiction != "" {
+			c.Jurisdiction = strings.ToLower(*block.Jurisdiction)
+		}
+
+		if c.AccessKey == "" || c.SecretKey == "" {
+			return nil, fmt.Errorf("r2_credentials for account %s must set access_key and secret_key", accountID)
+		}
+		if !slices.Contains(r2Jurisdictions, c.Jurisdiction) {
+			return nil, fmt.Errorf("r2_credentials for account %s has invalid jurisdiction %q, must be one of %s", accountID, c.Jurisdiction, strings.Join(r2Jurisdictions, ", "))
+		}
+		if slices.ContainsFunc(creds, func(other r2Credentials) bool { return other.Jurisdiction == c.Jurisdiction }) {
+			return nil, fmt.Errorf("r2_credentials for account %s are configured more than once for the %s jurisdiction", accountID, c.Jurisdiction)
+		}
+		creds = append(creds, c)
+	}
+	if len(creds) > 0 {
+		return creds, nil
+	}
+
+	var accessKey, secret string
+	if config.AccessKey != nil {
+		accessKey = *config.AccessKey
+	}
+	if config.SecretKey != nil {
+		secret = *config.SecretKey
+	}
+	if accessKey == "" || secret == "" {
+		return nil, errR2CredentialsNotFound
+	}
+
+	return []r2Credentials{{AccessKey: accessKey, SecretKey: secret, Jurisdiction: r2JurisdictionDefault}}, nil
+}
+
+// getR2Jurisdictions returns the jurisdictions R2 credentials are configured
+// for in an account, narrowed to the jurisdiction qual if one is given.
+func getR2Jurisdictions(d *plugin.QueryData, accountID string) ([]string, error) {
+	creds, err := getR2Credentials(GetConfig(d.Connection), accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	qual, err := r2JurisdictionQual(d)
+	if err != nil {
+		return nil, err
+	}
+	var jurisdictions []string
+	for _, c := range creds {
+		if qual == "" || qual == c.Jurisdiction {
+			jurisdictions = append(jurisdictions, c.Jurisdiction)
+		}
+	}
+	return jurisdictions, nil
+}
+
+// Create Cloudflare R2 API client
+func getR2Client(ctx context.Context, d *plugin.QueryData, accountID string, jurisdiction string) (*s3.Client, error) {
+	sessionCacheKey := fmt.Sprintf("session-v2-%s-%s", accountID, jurisdiction)
+	if cachedData, ok := d.ConnectionManager.Cache.Get(sessionCacheKey); ok {
+		return cachedData.(*s3.Client), nil
+	}
+
+	cloudflareConfig := GetConfig(d.Connection)
+	creds, err := getR2Credentials(cloudflareConfig, accountID)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(creds, func(c r2Credentials) bool { return c.Jurisdiction == jurisdiction })
+	if i == -1 {
+		return nil, fmt.Errorf("no R2 credentials configured for account %s in the %s jurisdiction", accountID, jurisdiction)
+	}
+
+	endpoint, customEndpoint := getR2Endpoint(cloudflareConfig, accountID, jurisdiction)
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid R2 endpoint %q: %v", endpoint, err)
+	}
+
+	r2EndpointResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			URL: endpoint,
+		}, nil
+	})
+
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithEndpointResolverWithOptions(r2EndpointResolver),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(creds[i].AccessKey, creds[i].SecretKey, "")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// Local stand-ins and proxies rarely serve bucket subdomains, so use
+		// path-style addressing whenever the endpoint has been overridden
+		o.UsePathStyle = customEndpoint
+	})
+
+	d.ConnectionManager.Cache.Set(sessionCacheKey, client)
+
+	return client, nil
+}
+
+// getR2BucketClient returns an R2 client for the jurisdiction a bucket lives
+// in. The jurisdiction comes from the jurisdiction qual if given, otherwise
+// each jurisdiction configured for the account is probed with HeadBucket and
+// the answer cached for the connection.
+func getR2BucketClient(ctx context.Context, d *plugin.QueryData, accountID string, bucket string) (*s3.Client, string, error) {
+	jurisdictions, err := getR2Jurisdictions(d, accountID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(jurisdictions) == 0 {
+		return nil, "", fmt.Errorf("no R2 credentials configured for account %s in the %s jurisdiction", accountID, d.EqualsQualString("jurisdiction"))
+	}
+	if len(jurisdictions) == 1 {
+		conn, err := getR2Client(ctx, d, accountID, jurisdictions[0])
+		return conn, jurisdictions[0], err
+	}
+
+	cacheKey := fmt.Sprintf("r2-bucket-jurisdiction-%s-%s", accountID, bucket)
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		jurisdiction := cachedData.(string)
+		conn, err := getR2Client(ctx, d, accountID, jurisdiction)
+		return conn, jurisdiction, err
+	}
+
+	for _, jurisdiction := range jurisdictions {
+		conn, err := getR2Client(ctx, d, accountID, jurisdiction)
+		if err != nil {
+			return nil, "", err
+		}
+		_, err = conn.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return nil, "", err
+		}
+		d.ConnectionManager.Cache.Set(cacheKey, jurisdiction)
+		return conn, jurisdiction, nil
+	}
+
+	// Fall back to the first jurisdiction so the caller gets the usual
+	// NoSuchBucket error from the API
+	conn, err := getR2Client(ctx, d, accountID, jurisdictions[0])
+	return conn, jurisdictions[0], err
+}
+
+// getR2Endpoint returns the R2 S3 API endpoint for an account and jurisdiction
+// from config or environment variables. A configured endpoint may contain
+// {account_id} and {jurisdiction} placeholders, which are replaced with the
+// account ID and jurisdiction name. The second return value reports whether
+// the endpoint was overridden.
+func getR2Endpoint(config cloudflareConfig, accountID string, jurisdiction string) (string, bool) {
+	endpoint := ""
+	if config.R2Endpoint != nil && *config.R2Endpoint != "" {
+		endpoint = *config.R2Endpoint
+	} else if value := os.Getenv("CLOUDFLARE_R2_ENDPOINT"); value != "" {
+		endpoint = value
+	}
+
+	if endpoint == "" {
+		if jurisdiction == "" || jurisdiction == r2JurisdictionDefault {
+			return fmt.Sprintf("https://%s.r2.cloudflarestorage.com", accountID), false
+		}
+		return fmt.Sprintf("https://%s.%s.r2.cloudflarestorage.com", accountID, jurisdiction), false
+	}
+
+	if jurisdiction == "" {
+		jurisdiction = r2JurisdictionDefault
+	}
+	endpoint = strings.ReplaceAll(endpoint, "{account_id}", accountID)
+	return strings.ReplaceAll(endpoint, "{jurisdiction}", jurisdiction), true
+}
+
+// getMaxObjectDataSize returns the largest object body, in bytes, that the
+// plugin will read into memory from config or environment variables. 0 means
+// no limit.
+func getMaxObjectDataSize(config cloudflareConfig) int64 {
+	// Default to 100 MiB
+	defaultSize := int64(100 * 1024 * 1024)
+
+	if config.MaxObjectDataSize != nil {
+		return *config.MaxObjectDataSize
+	}
+
+	// Check environment variable
+	if sizeStr := os.Getenv("CLOUDFLARE_MAX_OBJECT_DATA_SIZE"); sizeStr != "" {
+		if size, err := strconv.ParseInt(sizeStr, 10, 64); err == nil && size >= 0 {
+			return size
+		}
+	}
+
+	return defaultSize
+}
+
+// Compression formats that object bodies are transparently decoded from
+const (
+	r2CompressionGzip = "gzip"
+	r2CompressionZstd = "zstd"
+)
+
+// r2ObjectCompression returns the compression format of an object from its
+// Content-Encoding, falling back to the key's file extension, or "" if the
+// object is not compressed.
+func r2ObjectCompression(contentEncoding string, key string) string {
+	for _, encoding := range strings.Split(strings.ToLower(contentEncoding), ",") {
+		switch strings.TrimSpace(encoding) {
+		case "gzip", "x-gzip":
+			return r2CompressionGzip
+		case "zstd":
+			return r2CompressionZstd
+		}
+	}
+
+	switch strings.ToLower(path.Ext(key)) {
+	case ".gz", ".gzip":
+		return r2CompressionGzip
+	case ".zst", ".zstd":
+		return r2CompressionZstd
+	}
+	return ""
+}
+
+// newR2DecompressReader wraps body in a decoder for the compression format
+func newR2DecompressReader(body io.Reader, compression string) (io.ReadCloser, error) {
+	switch compression {
+	case r2CompressionGzip:
+		return gzip.NewReader(body)
+	case r2CompressionZstd:
+		decoder, err := zstd.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}
+	return io.NopCloser(body), nil
+}
+
+// readR2ObjectBody reads an object body, decompressing it if compression is
+// set. At most maxSize bytes are returned, larger bodies are an error. A
+// partial body (truncated is true) is read up to the point the compressed
+// stream was cut off.
+func readR2ObjectBody(body io.Reader, compression string, maxSize int64, truncated bool) ([]byte, error) {
+	reader, err := newR2DecompressReader(body, compression)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing %s data: %w", compression, err)
+	}
+	defer reader.Close()
+
+	var src io.Reader = reader
+	if maxSize > 0 {
+		src = io.LimitReader(reader, maxSize+1)
+	}
+	data, err := io.ReadAll(src)
+	if err != nil && !(truncated && errors.Is(err, io.ErrUnexpectedEOF)) {
+		if compression != "" {
+			return nil, fmt.Errorf("decompressing %s data: %w", compression, err)
+		}
+		return nil, err
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("decompressed data is larger than max_object_data_size of %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+// getR2APIJurisdictions returns the jurisdictions to read through the
+// Cloudflare API, which needs no S3 keys: the jurisdiction qual if given,
+// otherwise every jurisdiction.
+func getR2APIJurisdictions(d *plugin.QueryData) ([]string, error) {
+	qual, err := r2JurisdictionQual(d)
+	if err != nil || qual != "" {
+		return []string{qual}, err
+	}
+	return r2Jurisdictions, nil
+}
+
+// r2JurisdictionQual returns the jurisdiction qual, or an error if it is not
+// a jurisdiction R2 supports.
+func r2JurisdictionQual(d *plugin.QueryData) (string, error) {
+	qual := d.EqualsQualString("jurisdiction")
+	if qual != "" && !slices.Contains(r2Jurisdictions, qual) {
+		return "", fmt.Errorf("invalid jurisdiction %q, must be one of %s", qual, strings.Join(r2Jurisdictions, ", "))
+	}
+	return qual, nil
+}
+
+// isR2JurisdictionUnavailable reports whether an API error reading a
+// jurisdiction that was not asked for means the account does not use it.
+// Most accounts cannot use the eu or fedramp jurisdictions, which the API
+// answers as forbidden or not found; errors in the default jurisdiction, or
+// in one named by the jurisdiction qual, are still returned.
+func isR2JurisdictionUnavailable(d *plugin.QueryData, jurisdiction string, err error) bool {
+	if jurisdiction == r2JurisdictionDefault || d.EqualsQualString("jurisdiction") != "" {
+		return false
+	}
+	return isForbiddenError(err) || isNotFoundError(err)
+}
+
+// s3APITags returns the rate limiter tags of calls to the R2 S3 API, which is
+// not subject to the v4 API limiters. The SDK adds to a tags map, so each
+// table and hydrate config gets a map of its own.
+func s3APITags() map[string]string {
+	return map[string]string{"api": "s3"}
+}
+
+// listR2ObjectPages pages through the objects in a bucket, calling page with
+// each page of results until it returns false.
+func listR2ObjectPages(ctx context.Context, conn *s3.Client, input *s3.ListObjectsV2Input, page func(*s3.ListObjectsV2Output) bool) error {
+	paginator := s3.NewListObjectsV2Paginator(conn, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if !page(output) {
+			return nil
+		}
+	}
+	return nil
+}
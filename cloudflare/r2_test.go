@@ -0,0 +1,180 @@
+package cloudflare
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/rate_limiter"
+)
+
+// r2JurisdictionConfig gives the first account separate default and EU
+// credentials and the second account credentials of its own. The endpoint
+// puts the jurisdiction in the path so each resolves to its own fixtures.
+const r2JurisdictionConfig = `
+r2_endpoint = "{server_url}/r2/{jurisdiction}/{account_id}"
+
+r2_credentials "01a7362d577a6c3019a474fd6f485823" {
+  access_key = "default-access-key"
+  secret_key = "default-secret-key"
+}
+
+r2_credentials "01a7362d577a6c3019a474fd6f485823" {
+  access_key   = "eu-access-key"
+  secret_key   = "eu-secret-key"
+  jurisdiction = "eu"
+}
+
+r2_credentials "2b0e4c8f1d3a5e7c9b2d4f6a8c0e2a4b" {
+  access_key = "second-access-key"
+  secret_key = "second-secret-key"
+}
+`
+
+func TestGetR2Endpoint(t *testing.T) {
+	t.Setenv("CLOUDFLARE_R2_ENDPOINT", "")
+	tests := []struct {
+		name         string
+		config       cloudflareConfig
+		jurisdiction string
+		want         string
+		custom       bool
+	}{
+		{"default", cloudflareConfig{}, r2JurisdictionDefault, "https://abc.r2.cloudflarestorage.com", false},
+		{"eu", cloudflareConfig{}, r2JurisdictionEU, "https://abc.eu.r2.cloudflarestorage.com", false},
+		{"fedramp", cloudflareConfig{}, r2JurisdictionFedRAMP, "https://abc.fedramp.r2.cloudflarestorage.com", false},
+		{"custom", cloudflareConfig{R2Endpoint: aws.String("http://localhost:9000/{jurisdiction}/{account_id}")}, r2JurisdictionEU, "http://localhost:9000/eu/abc", true},
+	}
+	for _, tt := range tests {
+		got, custom := getR2Endpoint(tt.config, "abc", tt.jurisdiction)
+		if got != tt.want || custom != tt.custom {
+			t.Errorf("%s: getR2Endpoint() = %q, %v, want %q, %v", tt.name, got, custom, tt.want, tt.custom)
+		}
+	}
+}
+
+func TestGetR2Credentials(t *testing.T) {
+	block := func(jurisdiction string) r2CredentialsConfig {
+		return r2CredentialsConfig{AccountID: "abc", AccessKey: aws.String("key"), SecretKey: aws.String("secret"), Jurisdiction: aws.String(jurisdiction)}
+	}
+
+	// Accounts without a block use the connection's key pair
+	config := cloudflareConfig{AccessKey: aws.String("key"), SecretKey: aws.String("secret"), R2Credentials: []r2CredentialsConfig{block("EU")}}
+	creds, err := getR2Credentials(config, "other")
+	if err != nil || len(creds) != 1 || creds[0].Jurisdiction != r2JurisdictionDefault {
+		t.Errorf("fallback credentials = %+v, %v", creds, err)
+	}
+	creds, err = getR2Credentials(config, "abc")
+	if err != nil || len(creds) != 1 || creds[0].Jurisdiction != r2JurisdictionEU {
+		t.Errorf("account credentials = %+v, %v", creds, err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		blocks []r2CredentialsConfig
+		want   string
+	}{
+		{"invalid jurisdiction", []r2CredentialsConfig{block("us")}, "invalid jurisdiction"},
+		{"duplicate jurisdiction", []r2CredentialsConfig{block(""), block("default")}, "more than once"},
+		{"missing secret", []r2CredentialsConfig{{AccountID: "abc", AccessKey: aws.String("key")}}, "must set access_key and secret_key"},
+	} {
+		_, err := getR2Credentials(cloudflareConfig{R2Credentials: tt.blocks}, "abc")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want it to contain %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestR2ObjectCompression(t *testing.T) {
+	tests := []struct {
+		contentEncoding, key, want string
+	}{
+		{"gzip", "app.log", r2CompressionGzip},
+		{"identity, x-gzip", "app.log", r2CompressionGzip},
+		{"zstd", "app.log", r2CompressionZstd},
+		{"", "app.log.gz", r2CompressionGzip},
+		{"", "app.log.ZST", r2CompressionZstd},
+		{"", "app.log", ""},
+		{"br", "app.json", ""},
+	}
+	for _, tt := range tests {
+		if got := r2ObjectCompression(tt.contentEncoding, tt.key); got != tt.want {
+			t.Errorf("r2ObjectCompression(%q, %q) = %q, want %q", tt.contentEncoding, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestReadR2ObjectBodyTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	for i := range 2000 {
+		fmt.Fprintf(w, "line %d\n", i)
+	}
+	_ = w.Close()
+	partial := buf.Bytes()[:buf.Len()/2]
+
+	// The first bytes of a compressed object decode to the start of the data
+	data, err := readR2ObjectBody(bytes.NewReader(partial), r2CompressionGzip, 0, true)
+	if err != nil || !strings.HasPrefix(string(data), "line 0\nline 1\n") {
+		t.Errorf("reading a partial range: %q, %v", data, err)
+	}
+
+	// but a cut off object is an error when the whole object was requested
+	if _, err := readR2ObjectBody(bytes.NewReader(partial), r2CompressionGzip, 0, false); err == nil {
+		t.Error("expected an error reading a truncated object")
+	}
+}
+
+func TestCloudflareAPILimitersSkipS3Calls(t *testing.T) {
+	p := Plugin(context.Background())
+	limiters := map[string]*rate_limiter.Definition{}
+	for _, l := range p.RateLimiters {
+		if err := l.Initialise(); err != nil {
+			t.Fatal(err)
+		}
+		limiters[l.Name] = l
+	}
+
+	// scopeValues resolves the scope values of a call the way the SDK does,
+	// with the hydrate config's tags taking precedence over the table's
+	scopeValues := func(table string, hydrate plugin.HydrateFunc) map[string]string {
+		values := map[string]string{"connection": "cloudflare", "table": table}
+		maps.Copy(values, p.TableMap[table].Tags)
+		for _, c := range p.TableMap[table].HydrateConfig {
+			if reflect.ValueOf(c.Func).Pointer() == reflect.ValueOf(hydrate).Pointer() {
+				maps.Copy(values, c.Tags)
+			}
+		}
+		return values
+	}
+
+	tests := []struct {
+		table   string
+		hydrate plugin.HydrateFunc
+		want    bool
+	}{
+		{"cloudflare_zone", listZones, true},
+		{"cloudflare_r2_bucket", listR2Buckets, true},
+		{"cloudflare_r2_bucket", getR2BucketLifecycle, true},
+		{"cloudflare_r2_bucket", getBucketCORS, false},
+		{"cloudflare_r2_bucket", getBucketLocation, false},
+		{"cloudflare_r2_object", listR2Objects, false},
+		{"cloudflare_r2_object_data", getR2ObjectData, false},
+	}
+	for _, tt := range tests {
+		values := scopeValues(tt.table, tt.hydrate)
+		for _, name := range []string{"cloudflare_api", "cloudflare_api_table"} {
+			if got := limiters[name].SatisfiesFilters(values); got != tt.want {
+				t.Errorf("%s limits %s of %s = %v, want %v", name, runtime.FuncForPC(reflect.ValueOf(tt.hydrate).Pointer()).Name(), tt.table, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,777 @@
+package cloudflare
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Parser for the Cloudflare Rules language, the wirefilter grammar that
+// ruleset rule expressions are written in.
+// See https://developers.cloudflare.com/ruleset-engine/rules-language/
+//
+// Expressions stored by Cloudflare have already been validated, so the parser
+// is lenient where the grammar is loose and only checks syntax: field names,
+// function names and the types of values are not checked.
+
+// Nodes of the parsed expression, marshalled as the expression_ast column.
+// Every node has a type: logical, not, comparison, field, function, list,
+// named_list, string, int, bool, bytes, ip, cidr, ip_range or int_range.
+type exprNode interface{}
+
+type exprLogical struct {
+	Type     string     `json:"type"`
+	Operator string     `json:"operator"`
+	Operands []exprNode `json:"operands"`
+}
+
+type exprNot struct {
+	Type    string   `json:"type"`
+	Operand exprNode `json:"operand"`
+}
+
+type exprComparison struct {
+	Type     string   `json:"type"`
+	Operator string   `json:"operator"`
+	LHS      exprNode `json:"lhs"`
+	RHS      exprNode `json:"rhs"`
+}
+
+type exprField struct {
+	Type    string        `json:"type"`
+	Name    string        `json:"name"`
+	Indexes []interface{} `json:"indexes,omitempty"`
+}
+
+type exprFunction struct {
+	Type    string        `json:"type"`
+	Name    string        `json:"name"`
+	Args    []exprNode    `json:"args"`
+	Indexes []interface{} `json:"indexes,omitempty"`
+}
+
+type exprList struct {
+	Type  string     `json:"type"`
+	Items []exprNode `json:"items"`
+}
+
+type exprNamedList struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type exprLiteral struct {
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type exprRange struct {
+	Type string      `json:"type"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Logical operators from the lowest to the highest precedence, with their
+// symbol forms. not binds tighter than all of them.
+var exprLogicalOperators = []struct{ word, symbol string }{
+	{"or", "||"},
+	{"xor", "^^"},
+	{"and", "&&"},
+}
+
+// Comparison operators in symbol form, longest first, and their word forms
+var (
+	exprComparisonSymbols = []struct{ symbol, word string }{
+		{"==", "eq"}, {"!=", "ne"}, {"<=", "le"}, {">=", "ge"}, {"<", "lt"}, {">", "gt"}, {"~", "matches"},
+	}
+	exprComparisonWords = map[string]bool{
+		"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+		"contains": true, "matches": true, "in": true, "wildcard": true,
+	}
+)
+
+// exprBytesPattern matches byte strings such as 0a:1b:2c
+var exprBytesPattern = regexp.MustCompile(`^[0-9A-Fa-f]{2}([:.-][0-9A-Fa-f]{2})*$`)
+
+type exprSyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *exprSyntaxError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos+1)
+}
+
+type exprParser struct {
+	input string
+	pos   int
+}
+
+// parseExpression parses a rule expression into its syntax tree.
+func parseExpression(input string) (exprNode, error) {
+	p := &exprParser{input: input}
+	node, err := p.parseLogical(0)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %s", p.describeNext())
+	}
+	return node, nil
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return &exprSyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// describeNext describes the input at the current position for errors
+func (p *exprParser) describeNext() string {
+	if p.pos >= len(p.input) {
+		return "end of expression"
+	}
+	if word := p.peekWord(); word != "" {
+		return strconv.Quote(word)
+	}
+	// Describe a bare value as a whole, and punctuation by itself
+	const delimiters = " \t\r\n(){}[],\""
+	end := p.pos + 1
+	for !strings.ContainsRune(delimiters, rune(p.input[p.pos])) && end < len(p.input) && !strings.ContainsRune(delimiters, rune(p.input[end])) {
+		end++
+	}
+	return strconv.Quote(p.input[p.pos:end])
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) peekByte() byte {
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// accept consumes s if the input continues with it after any spaces
+func (p *exprParser) accept(s string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+// peekWord returns the identifier at the current position without consuming
+// it, or "" if there is none.
+func (p *exprParser) peekWord() string {
+	end := p.pos
+	for end < len(p.input) && isExprIdentByte(p.input[end], end == p.pos) {
+		end++
+	}
+	// Dots only join the parts of a field name
+	for end < len(p.input)-1 && p.input[end] == '.' && isExprIdentByte(p.input[end+1], false) {
+		end++
+		for end < len(p.input) && isExprIdentByte(p.input[end], false) {
+			end++
+		}
+	}
+	return p.input[p.pos:end]
+}
+
+// acceptWord consumes word if it is the whole identifier after any spaces
+func (p *exprParser) acceptWord(word string) bool {
+	p.skipSpace()
+	if p.peekWord() == word {
+		p.pos += len(word)
+		return true
+	}
+	return false
+}
+
+func isExprIdentByte(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+// parseLogical parses operands joined by the logical operator at level, or
+// by operators of higher precedence.
+func (p *exprParser) parseLogical(level int) (exprNode, error) {
+	if level == len(exprLogicalOperators) {
+		return p.parseTerm()
+	}
+	op := exprLogicalOperators[level]
+
+	first, err := p.parseLogical(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	operands := []exprNode{first}
+	for p.acceptWord(op.word) || p.accept(op.symbol) {
+		next, err := p.parseLogical(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &exprLogical{Type: "logical", Operator: op.word, Operands: operands}, nil
+}
+
+// parseTerm parses a negation, a parenthesized expression, a boolean literal,
+// a comparison, or a field or function used as a boolean.
+func (p *exprParser) parseTerm() (exprNode, error) {
+	p.skipSpace()
+	if p.acceptWord("not") || (p.peekByte() == '!' && !strings.HasPrefix(p.input[p.pos:], "!=") && p.accept("!")) {
+		operand, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return &exprNot{Type: "not", Operand: operand}, nil
+	}
+	if p.accept("(") {
+		node, err := p.parseLogical(0)
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("expected \")\", found %s", p.describeNext())
+		}
+		return node, nil
+	}
+	if word := p.peekWord(); word == "true" || word == "false" {
+		p.pos += len(word)
+		return &exprLiteral{Type: "bool", Value: word == "true"}, nil
+	}
+
+	lhs, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.parseComparisonOperator()
+	if !ok {
+		return lhs, nil
+	}
+	rhs, err := p.parseComparisonValue(op)
+	if err != nil {
+		return nil, err
+	}
+	return &exprComparison{Type: "comparison", Operator: op, LHS: lhs, RHS: rhs}, nil
+}
+
+// parseOperand parses a field or a function call, with any indexes such as
+// http.request.headers["accept"][0].
+func (p *exprParser) parseOperand() (exprNode, error) {
+	p.skipSpace()
+	name := p.peekWord()
+	if name == "" {
+		return nil, p.errorf("expected a field or function, found %s", p.describeNext())
+	}
+	p.pos += len(name)
+
+	if p.accept("(") {
+		args := []exprNode{}
+		if !p.accept(")") {
+			for {
+				arg, err := p.parseArgument()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if p.accept(",") {
+					continue
+				}
+				if p.accept(")") {
+					break
+				}
+				return nil, p.errorf("expected \",\" or \")\", found %s", p.describeNext())
+			}
+		}
+		indexes, err := p.parseIndexes()
+		if err != nil {
+			return nil, err
+		}
+		return &exprFunction{Type: "function", Name: name, Args: args, Indexes: indexes}, nil
+	}
+
+	indexes, err := p.parseIndexes()
+	if err != nil {
+		return nil, err
+	}
+	return &exprField{Type: "field", Name: name, Indexes: indexes}, nil
+}
+
+// parseIndexes parses map keys, array indexes and [*] after a field
+func (p *exprParser) parseIndexes() ([]interface{}, error) {
+	var indexes []interface{}
+	for p.accept("[") {
+		p.skipSpace()
+		switch c := p.peekByte(); {
+		case c == '*':
+			p.pos++
+			indexes = append(indexes, "*")
+		case c == '"':
+			key, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			indexes = append(indexes, key)
+		case c >= '0' && c <= '9':
+			start := p.pos
+			for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+				p.pos++
+			}
+			index, _ := strconv.Atoi(p.input[start:p.pos])
+			indexes = append(indexes, index)
+		default:
+			return nil, p.errorf("expected an index, found %s", p.describeNext())
+		}
+		if !p.accept("]") {
+			return nil, p.errorf("expected \"]\", found %s", p.describeNext())
+		}
+	}
+	return indexes, nil
+}
+
+// parseArgument parses a function argument: a value, or an expression as in
+// any(http.request.headers.names[*] == "x-api-key").
+func (p *exprParser) parseArgument() (exprNode, error) {
+	p.skipSpace()
+	if c := p.peekByte(); c == '"' || c == '{' || c == '$' || c == '-' || (c >= '0' && c <= '9') || p.atRawString() {
+		return p.parseValue()
+	}
+	return p.parseLogical(0)
+}
+
+// parseComparisonOperator consumes a comparison operator and returns its word
+// form, or reports false if there is none.
+func (p *exprParser) parseComparisonOperator() (string, bool) {
+	p.skipSpace()
+	for _, op := range exprComparisonSymbols {
+		if strings.HasPrefix(p.input[p.pos:], op.symbol) {
+			p.pos += len(op.symbol)
+			return op.word, true
+		}
+	}
+	word := p.peekWord()
+	if exprComparisonWords[word] {
+		p.pos += len(word)
+		return word, true
+	}
+	if word == "strict" {
+		start := p.pos
+		p.pos += len(word)
+		if p.acceptWord("wildcard") {
+			return "strict wildcard", true
+		}
+		p.pos = start
+	}
+	return "", false
+}
+
+// parseComparisonValue parses the right-hand side of a comparison. in takes a
+// list or a named list, and the other operators a single value.
+func (p *exprParser) parseComparisonValue(op string) (exprNode, error) {
+	p.skipSpace()
+	isList := p.peekByte() == '{' || p.peekByte() == '$'
+	if op == "in" && !isList {
+		return nil, p.errorf("expected a list after in, found %s", p.describeNext())
+	}
+	if op != "in" && p.peekByte() == '{' {
+		return nil, p.errorf("a list can only be compared with in")
+	}
+	return p.parseValue()
+}
+
+// parseValue parses a literal: a string, a list, a named list, or a bare
+// value such as an integer, an IP address, a CIDR or a range.
+func (p *exprParser) parseValue() (exprNode, error) {
+	p.skipSpace()
+	switch c := p.peekByte(); {
+	case c == '"' || p.atRawString():
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return &exprLiteral{Type: "string", Value: s}, nil
+	case c == '{':
+		p.pos++
+		items := []exprNode{}
+		for {
+			p.skipSpace()
+			if p.accept("}") {
+				return &exprList{Type: "list", Items: items}, nil
+			}
+			if p.pos >= len(p.input) {
+				return nil, p.errorf("expected \"}\", found end of expression")
+			}
+			if p.peekByte() == '{' {
+				return nil, p.errorf("lists cannot be nested")
+			}
+			item, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			// Tolerate commas between items
+			p.accept(",")
+		}
+	case c == '$':
+		p.pos++
+		name := p.peekWord()
+		if name == "" {
+			return nil, p.errorf("expected a list name, found %s", p.describeNext())
+		}
+		p.pos += len(name)
+		return &exprNamedList{Type: "named_list", Name: name}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && (isExprIdentByte(p.input[p.pos], false) || strings.ContainsRune(":./-", rune(p.input[p.pos]))) {
+		p.pos++
+	}
+	bare := p.input[start:p.pos]
+	if bare == "" {
+		return nil, p.errorf("expected a value, found %s", p.describeNext())
+	}
+	if node := parseBareValue(bare); node != nil {
+		return node, nil
+	}
+
+	// Anything else that looks like a name is taken as a field
+	p.pos = start
+	if word := p.peekWord(); word != "" {
+		return p.parseOperand()
+	}
+	return nil, &exprSyntaxError{Pos: start, Msg: fmt.Sprintf("invalid value %q", bare)}
+}
+
+// atRawString reports whether a raw string such as r"..." or r#"..."#
+// starts at the current position.
+func (p *exprParser) atRawString() bool {
+	rest := p.input[p.pos:]
+	if !strings.HasPrefix(rest, "r") {
+		return false
+	}
+	return strings.HasPrefix(strings.TrimLeft(rest[1:], "#"), `"`)
+}
+
+// parseString parses a quoted string, unescaping \" \\ and \xHH, or a raw
+// string with no escapes.
+func (p *exprParser) parseString() (string, error) {
+	start := p.pos
+	if p.atRawString() {
+		p.pos++
+		hashes := 0
+		for p.input[p.pos] == '#' {
+			hashes++
+			p.pos++
+		}
+		p.pos++
+		terminator := `"` + strings.Repeat("#", hashes)
+		end := strings.Index(p.input[p.pos:], terminator)
+		if end < 0 {
+			return "", &exprSyntaxError{Pos: start, Msg: "unterminated raw string"}
+		}
+		s := p.input[p.pos : p.pos+end]
+		p.pos += end + len(terminator)
+		return s, nil
+	}
+
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch {
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.input):
+			next := p.input[p.pos+1]
+			switch {
+			case next == '"' || next == '\\':
+				b.WriteByte(next)
+				p.pos += 2
+			case next == 'x' && p.pos+3 < len(p.input):
+				if v, err := strconv.ParseUint(p.input[p.pos+2:p.pos+4], 16, 8); err == nil {
+					b.WriteByte(byte(v))
+					p.pos += 4
+					continue
+				}
+				fallthrough
+			default:
+				// Keep other escapes as written, as regular expressions need them
+				b.WriteByte(c)
+				b.WriteByte(next)
+				p.pos += 2
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", &exprSyntaxError{Pos: start, Msg: "unterminated string"}
+}
+
+// parseBareValue classifies an unquoted value, or returns nil if it is not a
+// value.
+func parseBareValue(s string) exprNode {
+	if from, to, ok := strings.Cut(s, ".."); ok {
+		if a, err := strconv.ParseInt(from, 10, 64); err == nil {
+			if b, err := strconv.ParseInt(to, 10, 64); err == nil && a <= b {
+				return &exprRange{Type: "int_range", From: a, To: b}
+			}
+			return nil
+		}
+		a, errA := netip.ParseAddr(from)
+		b, errB := netip.ParseAddr(to)
+		if errA != nil || errB != nil || a.Is4() != b.Is4() || a.Compare(b) > 0 {
+			return nil
+		}
+		return &exprRange{Type: "ip_range", From: a.String(), To: b.String()}
+	}
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil
+		}
+		return &exprLiteral{Type: "cidr", Value: prefix.Masked().String()}
+	}
+	if addr, err := netip.ParseAddr(s); err == nil {
+		return &exprLiteral{Type: "ip", Value: addr.String()}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return &exprLiteral{Type: "int", Value: n}
+	}
+	if exprBytesPattern.MatchString(s) {
+		return &exprLiteral{Type: "bytes", Value: strings.ToLower(s)}
+	}
+	return nil
+}
+
+//// ANALYSIS
+
+// Fields whose values are hostnames, and fields whose values are URLs
+var (
+	exprHostnameFields = map[string]bool{
+		"http.host":               true,
+		"cf.worker.upstream_zone": true,
+	}
+	exprURLFields = map[string]bool{
+		"http.referer":              true,
+		"http.request.full_uri":     true,
+		"raw.http.request.full_uri": true,
+	}
+)
+
+// ruleExpression is a parsed rule expression and the fields, IP addresses and
+// hostnames it references.
+type ruleExpression struct {
+	Valid     bool     `json:"valid"`
+	Error     string   `json:"error,omitempty"`
+	AST       exprNode `json:"-"`
+	Fields    []string `json:"fields"`
+	IPs       []string `json:"ips"`
+	Hostnames []string `json:"hostnames"`
+}
+
+// parseRuleExpression parses and analyzes a rule expression, or returns nil
+// if the rule has none.
+func parseRuleExpression(expression string) *ruleExpression {
+	if strings.TrimSpace(expression) == "" {
+		return nil
+	}
+	ast, err := parseExpression(expression)
+	if err != nil {
+		return &ruleExpression{Error: err.Error()}
+	}
+
+	fields := map[string]bool{}
+	ips := map[string]bool{}
+	hostnames := map[string]bool{}
+	walkExpression(ast, func(node exprNode) {
+		switch n := node.(type) {
+		case *exprField:
+			fields[n.Name] = true
+		case *exprLiteral:
+			if n.Type == "ip" || n.Type == "cidr" {
+				ips[n.Value.(string)] = true
+			}
+		case *exprRange:
+			if n.Type == "ip_range" {
+				from, _ := netip.ParseAddr(n.From.(string))
+				to, _ := netip.ParseAddr(n.To.(string))
+				for _, prefix := range ipRangePrefixes(from, to) {
+					ips[prefixString(prefix)] = true
+				}
+			}
+		case *exprComparison:
+			collectComparisonHostnames(n, hostnames)
+		case *exprFunction:
+			collectFunctionHostnames(n, hostnames)
+		}
+	})
+
+	return &ruleExpression{
+		Valid:     true,
+		AST:       ast,
+		Fields:    sortedKeys(fields),
+		IPs:       sortedKeys(ips),
+		Hostnames: sortedKeys(hostnames),
+	}
+}
+
+// walkExpression calls visit for node and each node below it
+func walkExpression(node exprNode, visit func(exprNode)) {
+	visit(node)
+	var children []exprNode
+	switch n := node.(type) {
+	case *exprLogical:
+		children = n.Operands
+	case *exprNot:
+		children = []exprNode{n.Operand}
+	case *exprComparison:
+		children = []exprNode{n.LHS, n.RHS}
+	case *exprFunction:
+		children = n.Args
+	case *exprList:
+		children = n.Items
+	}
+	for _, child := range children {
+		walkExpression(child, visit)
+	}
+}
+
+// exprFieldName returns the name of the field an operand reads, looking
+// through case conversions such as lower(http.host).
+func exprFieldName(node exprNode) string {
+	switch n := node.(type) {
+	case *exprField:
+		return n.Name
+	case *exprFunction:
+		if (n.Name == "lower" || n.Name == "upper" || n.Name == "to_string") && len(n.Args) == 1 {
+			return exprFieldName(n.Args[0])
+		}
+	}
+	return ""
+}
+
+// collectComparisonHostnames adds the hostnames a comparison matches a
+// hostname or URL field against.
+func collectComparisonHostnames(c *exprComparison, hostnames map[string]bool) {
+	switch c.Operator {
+	case "eq", "ne", "in", "wildcard", "strict wildcard":
+	default:
+		return
+	}
+	field := exprFieldName(c.LHS)
+	values := []exprNode{c.RHS}
+	if list, ok := c.RHS.(*exprList); ok {
+		values = list.Items
+	}
+	for _, value := range values {
+		if literal, ok := value.(*exprLiteral); ok && literal.Type == "string" {
+			addHostname(field, literal.Value.(string), hostnames)
+		}
+	}
+}
+
+// collectFunctionHostnames adds the hostnames of calls such as
+// ends_with(http.host, ".example.com").
+func collectFunctionHostnames(f *exprFunction, hostnames map[string]bool) {
+	if len(f.Args) != 2 {
+		return
+	}
+	literal, ok := f.Args[1].(*exprLiteral)
+	if !ok || literal.Type != "string" {
+		return
+	}
+	field := exprFieldName(f.Args[0])
+	value := literal.Value.(string)
+	switch {
+	case f.Name == "ends_with" && exprHostnameFields[field]:
+		addHostname(field, strings.TrimPrefix(value, "."), hostnames)
+	case f.Name == "starts_with" && exprURLFields[field]:
+		addHostname(field, value, hostnames)
+	}
+}
+
+// addHostname adds value if field holds hostnames, or the host of value if
+// field holds URLs.
+func addHostname(field string, value string, hostnames map[string]bool) {
+	switch {
+	case exprHostnameFields[field]:
+	case exprURLFields[field]:
+		u, err := url.Parse(value)
+		if err != nil || u.Host == "" {
+			return
+		}
+		value = u.Hostname()
+	default:
+		return
+	}
+	if value = strings.TrimSuffix(strings.ToLower(value), "."); value != "" {
+		hostnames[value] = true
+	}
+}
+
+// ipRangePrefixes returns the fewest CIDRs that exactly cover the range of
+// addresses from and to, inclusive.
+func ipRangePrefixes(from netip.Addr, to netip.Addr) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for from.IsValid() && from.Compare(to) <= 0 {
+		bits := from.BitLen()
+		for bits > 0 {
+			wider := netip.PrefixFrom(from, bits-1).Masked()
+			if wider.Addr() != from || lastPrefixAddr(wider).Compare(to) > 0 {
+				break
+			}
+			bits--
+		}
+		prefix := netip.PrefixFrom(from, bits)
+		prefixes = append(prefixes, prefix)
+		from = lastPrefixAddr(prefix).Next()
+	}
+	return prefixes
+}
+
+// lastPrefixAddr returns the last address of a prefix
+func lastPrefixAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - i%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// prefixString formats a prefix, leaving out the length of single addresses
+func prefixString(prefix netip.Prefix) string {
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String()
+	}
+	return prefix.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExpressionAST(t *testing.T) {
+	tests := []struct {
+		expression, want string
+	}{
+		{
+			`ip.src eq 192.0.2.1`,
+			`{"type":"comparison","operator":"eq","lhs":{"type":"field","name":"ip.src"},"rhs":{"type":"ip","value":"192.0.2.1"}}`,
+		},
+		{
+			`http.host == "a.example.com" or not ssl && cf.threat_score > 10`,
+			`{"type":"logical","operator":"or","operands":[` +
+				`{"type":"comparison","operator":"eq","lhs":{"type":"field","name":"http.host"},"rhs":{"type":"string","value":"a.example.com"}},` +
+				`{"type":"logical","operator":"and","operands":[{"type":"not","operand":{"type":"field","name":"ssl"}},` +
+				`{"type":"comparison","operator":"gt","lhs":{"type":"field","name":"cf.threat_score"},"rhs":{"type":"int","value":10}}]}]}`,
+		},
+		{
+			`(ip.src in {10.0.0.0/8 192.0.2.1..192.0.2.9} xor tcp.dstport in {80 8000..8080})`,
+			`{"type":"logical","operator":"xor","operands":[` +
+				`{"type":"comparison","operator":"in","lhs":{"type":"field","name":"ip.src"},"rhs":{"type":"list","items":[{"type":"cidr","value":"10.0.0.0/8"},{"type":"ip_range","from":"192.0.2.1","to":"192.0.2.9"}]}},` +
+				`{"type":"comparison","operator":"in","lhs":{"type":"field","name":"tcp.dstport"},"rhs":{"type":"list","items":[{"type":"int","value":80},{"type":"int_range","from":8000,"to":8080}]}}]}`,
+		},
+		{
+			`any(lower(http.request.headers["x-api-key"][*])[*] contains "test") and ip.src in $office_ips`,
+			`{"type":"logical","operator":"and","operands":[` +
+				`{"type":"function","name":"any","args":[{"type":"comparison","operator":"contains",` +
+				`"lhs":{"type":"function","name":"lower","args":[{"type":"field","name":"http.request.headers","indexes":["x-api-key","*"]}],"indexes":["*"]},` +
+				`"rhs":{"type":"string","value":"test"}}]},` +
+				`{"type":"comparison","operator":"in","lhs":{"type":"field","name":"ip.src"},"rhs":{"type":"named_list","name":"office_ips"}}]}`,
+		},
+		{
+			`http.request.uri.path matches r#"^/api/"v\d+"$"# and http.host strict wildcard "*.Example.com"`,
+			`{"type":"logical","operator":"and","operands":[` +
+				`{"type":"comparison","operator":"matches","lhs":{"type":"field","name":"http.request.uri.path"},"rhs":{"type":"string","value":"^/api/\"v\\d+\"$"}},` +
+				`{"type":"comparison","operator":"strict wildcard","lhs":{"type":"field","name":"http.host"},"rhs":{"type":"string","value":"*.Example.com"}}]}`,
+		},
+		{
+			`http.user_agent eq "say \"hi\" \x41\d" or true`,
+			`{"type":"logical","operator":"or","operands":[` +
+				`{"type":"comparison","operator":"eq","lhs":{"type":"field","name":"http.user_agent"},"rhs":{"type":"string","value":"say \"hi\" A\\d"}},` +
+				`{"type":"bool","value":true}]}`,
+		},
+	}
+	for _, tt := range tests {
+		ast, err := parseExpression(tt.expression)
+		if err != nil {
+			t.Errorf("parseExpression(%q) error = %v", tt.expression, err)
+			continue
+		}
+		got, _ := json.Marshal(ast)
+		if string(got) != tt.want {
+			t.Errorf("parseExpression(%q) = %s, want %s", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expression, want string
+	}{
+		{`ip.src eq`, "expected a value, found end of expression at position 10"},
+		{`(http.host eq "a"`, `expected ")", found end of expression at position 18`},
+		{`ip.src in 10.0.0.0/8`, "expected a list after in, found \"10.0.0.0/8\" at position 11"},
+		{`ip.src eq {10.0.0.1}`, "a list can only be compared with in at position 11"},
+		{`http.host eq "a`, "unterminated string at position 14"},
+		{`http.host eq "a" "b"`, `unexpected "\"" at position 18`},
+		{`ip.src eq 10.0.0.9..10.0.0.1`, `invalid value "10.0.0.9..10.0.0.1" at position 11`},
+		{`and ssl`, `unexpected "ssl" at position 5`},
+	}
+	for _, tt := range tests {
+		_, err := parseExpression(tt.expression)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("parseExpression(%q) error = %v, want %q", tt.expression, err, tt.want)
+		}
+	}
+}
+
+func TestParseRuleExpression(t *testing.T) {
+	if got := parseRuleExpression("  "); got != nil {
+		t.Errorf("parseRuleExpression(empty) = %+v, want nil", got)
+	}
+
+	got := parseRuleExpression(`(ip.src in {192.0.2.0/24 2001:db8::1 198.51.100.0..198.51.100.5} and not ip.src eq 203.0.113.7)` +
+		` or (lower(http.host) in {"A.example.com." "b.example.com"} and ends_with(http.host, ".example.net"))` +
+		` or starts_with(http.request.full_uri, "https://c.example.org:8443/path")` +
+		` or http.request.uri.path contains "example.com"`)
+	want := &ruleExpression{
+		Valid:     true,
+		Fields:    []string{"http.host", "http.request.full_uri", "http.request.uri.path", "ip.src"},
+		IPs:       []string{"192.0.2.0/24", "198.51.100.0/30", "198.51.100.4/31", "2001:db8::1", "203.0.113.7"},
+		Hostnames: []string{"a.example.com", "b.example.com", "c.example.org", "example.net"},
+	}
+	got.AST = nil
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRuleExpression() = %+v, want %+v", got, want)
+	}
+
+	invalid := parseRuleExpression(`ip.src eq`)
+	if invalid.Valid || !strings.HasPrefix(invalid.Error, "expected a value") || invalid.AST != nil {
+		t.Errorf("parseRuleExpression(invalid) = %+v, want an error", invalid)
+	}
+}
+
+func TestIPRangePrefixes(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     []string
+	}{
+		{"192.0.2.0", "192.0.2.255", []string{"192.0.2.0/24"}},
+		{"192.0.2.1", "192.0.2.1", []string{"192.0.2.1/32"}},
+		{"10.0.0.255", "10.0.2.0", []string{"10.0.0.255/32", "10.0.1.0/24", "10.0.2.0/32"}},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}},
+		{"255.255.255.254", "255.255.255.255", []string{"255.255.255.254/31"}},
+		{"2001:db8::", "2001:db8::3", []string{"2001:db8::/126"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, prefix := range ipRangePrefixes(netip.MustParseAddr(tt.from), netip.MustParseAddr(tt.to)) {
+			got = append(got, prefix.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ipRangePrefixes(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
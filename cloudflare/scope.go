@@ -0,0 +1,187 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/accounts"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+)
+
+// matchesScope reports whether a resource with the given ID and name matches
+// any of the patterns. A pattern matches when it equals the ID or when it is a
+// glob matching the name; both comparisons are case-insensitive.
+func matchesScope(patterns []string, id string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == strings.ToLower(id) {
+			return true, nil
+		}
+		ok, err := path.Match(pattern, strings.ToLower(name))
+		if err != nil {
+			return false, fmt.Errorf("invalid scope pattern %q: %w", pattern, err)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// accountInScope reports whether the account is allowed by the connection's
+// accounts filter. An empty filter allows every account.
+func accountInScope(d *plugin.QueryData, id string, name string) (bool, error) {
+	config := GetConfig(d.Connection)
+	if len(config.Accounts) == 0 {
+		return true, nil
+	}
+	return matchesScope(config.Accounts, id, name)
+}
+
+// zoneInScope reports whether the zone is allowed by the connection's
+// accounts, zones and exclude_zones filters.
+func zoneInScope(d *plugin.QueryData, zone zones.Zone) (bool, error) {
+	config := GetConfig(d.Connection)
+
+	ok, err := accountInScope(d, zone.Account.ID, zone.Account.Name)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if len(config.Zones) > 0 {
+		ok, err := matchesScope(config.Zones, zone.ID, zone.Name)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	excluded, err := matchesScope(config.ExcludeZones, zone.ID, zone.Name)
+	if err != nil {
+		return false, err
+	}
+	return !excluded, nil
+}
+
+// listAccountsAndZones is a parent hydrate for tables whose resources belong to
+// either an account or a zone. It streams the account or zone named by the
+// account_id or zone_id qual, or every account and zone in scope when there
+// is neither. Each resource belongs to an account or a zone, never both, so
+// both quals together are rejected rather than matching nothing.
+func listAccountsAndZones(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	accountID := d.EqualsQualString("account_id")
+	zoneID := d.EqualsQualString("zone_id")
+	if accountID != "" && zoneID != "" {
+		return nil, fmt.Errorf("account_id and zone_id cannot both be specified, as each %s row belongs to either an account or a zone", d.Table.Name)
+	}
+
+	if accountID != "" {
+		account, err := qualAccountInScope(ctx, d, accountID)
+		if err != nil || account == nil {
+			return nil, err
+		}
+		d.StreamListItem(ctx, *account)
+		return nil, nil
+	}
+	if zoneID != "" {
+		zone, err := qualZoneInScope(ctx, d, zoneID)
+		if err != nil || zone == nil {
+			return nil, err
+		}
+		d.StreamListItem(ctx, *zone)
+		return nil, nil
+	}
+
+	if _, err := listAccount(ctx, d, h); err != nil {
+		return nil, err
+	}
+	if d.RowsRemaining(ctx) == 0 {
+		return nil, nil
+	}
+	return listZones(ctx, d, h)
+}
+
+// qualAccountInScope returns the account with the given ID, or nil if it is
+// outside the connection's scope. The account is only fetched when the
+// accounts filter needs its name.
+func qualAccountInScope(ctx context.Context, d *plugin.QueryData, id string) (*accounts.Account, error) {
+	if len(GetConfig(d.Connection).Accounts) == 0 {
+		return &accounts.Account{ID: id}, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	account, err := conn.Accounts.Get(ctx, accounts.AccountGetParams{AccountID: cloudflare.F(id)})
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ok, err := accountInScope(d, account.ID, account.Name)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return account, nil
+}
+
+// qualZoneInScope returns the zone with the given ID, or nil if it is outside
+// the connection's scope. The zone is only fetched when the accounts, zones
+// or exclude_zones filters need its name or account.
+func qualZoneInScope(ctx context.Context, d *plugin.QueryData, id string) (*zones.Zone, error) {
+	config := GetConfig(d.Connection)
+	if len(config.Accounts) == 0 && len(config.Zones) == 0 && len(config.ExcludeZones) == 0 {
+		return &zones.Zone{ID: id}, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := conn.Zones.Get(ctx, zones.ZoneGetParams{ZoneID: cloudflare.F(id)})
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ok, err := zoneInScope(d, *zone)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return zone, nil
+}
+
+// accountOrZoneInScope reports whether the account or zone a get was
+// qualified with is in the connection's scope.
+func accountOrZoneInScope(ctx context.Context, d *plugin.QueryData, accountID string, zoneID string) (bool, error) {
+	if accountID != "" {
+		account, err := qualAccountInScope(ctx, d, accountID)
+		return account != nil, err
+	}
+	if zoneID != "" {
+		zone, err := qualZoneInScope(ctx, d, zoneID)
+		return zone != nil, err
+	}
+	return true, nil
+}
+
+// accountOrZoneScope returns the account or zone a child of
+// listAccountsAndZones lists resources for. Without a parent item, as in a
+// get, it falls back to the account_id and zone_id quals.
+func accountOrZoneScope(d *plugin.QueryData, h *plugin.HydrateData) (accountID string, zoneID string) {
+	if h != nil {
+		switch item := h.Item.(type) {
+		case accounts.Account:
+			return item.ID, ""
+		case zones.Zone:
+			return "", item.ID
+		}
+	}
+	return d.EqualsQualString("account_id"), d.EqualsQualString("zone_id")
+}
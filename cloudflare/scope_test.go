@@ -0,0 +1,32 @@
+package cloudflare
+
+import "testing"
+
+func TestMatchesScope(t *testing.T) {
+	cases := []struct {
+		patterns []string
+		id, name string
+		want     bool
+	}{
+		{nil, "023e105f4ecef8ad9ca31a8372d0c353", "example.com", false},
+		{[]string{"023E105F4ECEF8AD9CA31A8372D0C353"}, "023e105f4ecef8ad9ca31a8372d0c353", "example.com", true},
+		{[]string{"example.com"}, "023e105f4ecef8ad9ca31a8372d0c353", "Example.com", true},
+		{[]string{"*.example.com"}, "023e105f4ecef8ad9ca31a8372d0c353", "example.com", false},
+		{[]string{"*.example.com"}, "023e105f4ecef8ad9ca31a8372d0c353", "prod.example.com", true},
+		{[]string{"staging-*", "prod-*"}, "1", "prod-eu.example.net", true},
+		{[]string{"example.[co]rg"}, "1", "example.org", true},
+	}
+	for _, c := range cases {
+		got, err := matchesScope(c.patterns, c.id, c.name)
+		if err != nil {
+			t.Fatalf("matchesScope(%v, %q, %q): %v", c.patterns, c.id, c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("matchesScope(%v, %q, %q) = %v, want %v", c.patterns, c.id, c.name, got, c.want)
+		}
+	}
+
+	if _, err := matchesScope([]string{"[example"}, "1", "example.com"); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
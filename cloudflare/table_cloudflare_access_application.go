@@ -2,9 +2,7 @@ package cloudflare
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/accounts"
@@ -84,12 +82,10 @@ func listAccessApplications(ctx context.Context, d *plugin.QueryData, h *plugin.
 	iter := conn.ZeroTrust.Access.Applications.ListAutoPaging(ctx, opts)
 
 	if err := iter.Err(); err != nil {
-		var apiErr *cloudflare.Error
-		if errors.As(err, &apiErr) {
-			if strings.Contains(apiErr.Error(), "Access is not enabled. Visit the Access dashboard at https://dash.cloudflare.com/ and click the 'Enable Access' button.") {
-				logger.Warn("listAccessApplications", fmt.Sprintf("AccessApplications api error for account: %s", account.ID), err)
-				return nil, nil
-			}
+		// Access is not enabled for the account
+		if isForbiddenError(err) {
+			logger.Warn("listAccessApplications", fmt.Sprintf("AccessApplications api error for account: %s", account.ID), err)
+			return nil, nil
 		}
 		logger.Error("cloudflare_access_application.listAccessApplications", "AccessApplications api error", err)
 		return nil, err
@@ -0,0 +1,27 @@
+package cloudflare
+
+import "testing"
+
+func TestAccessApplicationList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_application")
+	rows := h.mustQuery("cloudflare_access_application", []string{"id", "name", "account_id", "account_name", "domain", "type", "session_duration", "created_at"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":               "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+			"name":             "Admin Site",
+			"account_id":       "01a7362d577a6c3019a474fd6f485823",
+			"account_name":     "Example Account",
+			"domain":           "admin.example.com",
+			"session_duration": "24h",
+			"created_at":       "2024-01-01T05:20:00Z",
+		},
+	})
+}
+
+func TestAccessApplicationAccessDisabled(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_disabled")
+	rows := h.mustQuery("cloudflare_access_application", []string{"id", "name"})
+
+	assertRows(t, rows, "id", nil)
+}
@@ -2,9 +2,7 @@ package cloudflare
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/accounts"
@@ -76,12 +74,10 @@ func listAccessGroups(ctx context.Context, d *plugin.QueryData, h *plugin.Hydrat
 	iter := conn.ZeroTrust.Access.Groups.ListAutoPaging(ctx, opts)
 
 	if err := iter.Err(); err != nil {
-		var apiErr *cloudflare.Error
-		if errors.As(err, &apiErr) {
-			if strings.Contains(apiErr.Error(), "Access is not enabled. Visit the Access dashboard at https://dash.cloudflare.com/ and click the 'Enable Access' button.") {
-				logger.Warn("listAccessGroups", fmt.Sprintf("AccessGroups api error for account: %s", account.ID), err)
-				return nil, nil
-			}
+		// Access is not enabled for the account
+		if isForbiddenError(err) {
+			logger.Warn("listAccessGroups", fmt.Sprintf("AccessGroups api error for account: %s", account.ID), err)
+			return nil, nil
 		}
 		logger.Error("cloudflare_access_group.listAccessGroups", "AccessGroups api error", err)
 		return nil, err
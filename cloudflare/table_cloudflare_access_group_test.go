@@ -0,0 +1,27 @@
+package cloudflare
+
+import "testing"
+
+func TestAccessGroupList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_group")
+	rows := h.mustQuery("cloudflare_access_group", []string{"id", "name", "account_id", "include", "exclude", "updated_at"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":         "aa0a4aab-672b-4bdb-bc33-a59f1130a11f",
+			"name":       "Engineers",
+			"account_id": "01a7362d577a6c3019a474fd6f485823",
+			"exclude":    []any{},
+			"updated_at": "2024-01-02T05:20:00Z",
+		},
+	})
+	include := rows[0]["include"].([]any)
+	assertValue(t, "include[0].email_domain", include[0].(map[string]any)["email_domain"], map[string]any{"domain": "example.com"})
+}
+
+func TestAccessGroupAccessDisabled(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_disabled")
+	rows := h.mustQuery("cloudflare_access_group", []string{"id"})
+
+	assertRows(t, rows, "id", nil)
+}
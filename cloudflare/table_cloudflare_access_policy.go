@@ -2,9 +2,7 @@ package cloudflare
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/zero_trust"
@@ -32,7 +30,7 @@ func tableCloudflareAccessPolicy(ctx context.Context) *plugin.Table {
 			{Name: "name", Type: proto.ColumnType_STRING, Description: "The name of the policy. Only used in the UI."},
 			{Name: "application_id", Type: proto.ColumnType_STRING, Hydrate: getParentApplicationDetails, Transform: transform.FromField("ID"), Description: "The id of application to which policy belongs."},
 			{Name: "application_name", Type: proto.ColumnType_STRING, Hydrate: getParentApplicationDetails, Transform: transform.FromField("Name"), Description: "The name of application to which policy belongs."},
-			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromMatrixItem(matrixKeyAccount), Description: "The ID of account where application belongs."},
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID"), Description: "The ID of account where application belongs."},
 
 			// Other columns
 			{Name: "created_at", Type: proto.ColumnType_TIMESTAMP, Description: "Timestamp when access policy was created."},
@@ -51,6 +49,13 @@ func tableCloudflareAccessPolicy(ctx context.Context) *plugin.Table {
 	}
 }
 
+// AccessPolicyInfo carries the account ID with each policy, as the SDK does not
+// pass the matrix item on to rows streamed by a child list call
+type AccessPolicyInfo struct {
+	AccountID string
+	zero_trust.AccessApplicationPolicyListResponse
+}
+
 func listAccessPolicies(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
 
@@ -83,7 +88,7 @@ func listAccessPolicies(ctx context.Context, d *plugin.QueryData, h *plugin.Hydr
 
 	for iter.Next() {
 		policy := iter.Current()
-		d.StreamListItem(ctx, policy)
+		d.StreamListItem(ctx, AccessPolicyInfo{accountID, policy})
 
 		// Context can be cancelled due to manual cancellation or the limit has been hit
 		if d.RowsRemaining(ctx) == 0 {
@@ -96,6 +101,10 @@ func listAccessPolicies(ctx context.Context, d *plugin.QueryData, h *plugin.Hydr
 
 func listParentAccessApplications(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
+	if err := accountMatrixError(ctx); err != nil {
+		logger.Error("cloudflare_access_policy.listParentAccessApplications", "account matrix error", err)
+		return nil, err
+	}
 	accountID := d.EqualsQualString(matrixKeyAccount)
 
 	conn, err := connectV4(ctx, d)
@@ -111,12 +120,10 @@ func listParentAccessApplications(ctx context.Context, d *plugin.QueryData, h *p
 	iter := conn.ZeroTrust.Access.Applications.ListAutoPaging(ctx, opts)
 
 	if err := iter.Err(); err != nil {
-		var apiErr *cloudflare.Error
-		if errors.As(err, &apiErr) {
-			if strings.Contains(apiErr.Error(), "Access is not enabled. Visit the Access dashboard at https://dash.cloudflare.com/ and click the 'Enable Access' button.") {
-				logger.Warn("listParentAccessApplications", fmt.Sprintf("AccessApplications api error for account: %s", accountID), err)
-				return nil, nil
-			}
+		// Access is not enabled for the account
+		if isForbiddenError(err) {
+			logger.Warn("listParentAccessApplications", fmt.Sprintf("AccessApplications api error for account: %s", accountID), err)
+			return nil, nil
 		}
 		logger.Error("cloudflare_access_policy.listParentAccessApplications", "AccessApplications api error", err)
 		return nil, err
@@ -0,0 +1,35 @@
+package cloudflare
+
+import "testing"
+
+func TestAccessPolicyList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_policy")
+	rows := h.mustQuery("cloudflare_access_policy", []string{"id", "name", "application_id", "application_name", "account_id", "decision", "precedence", "include"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":               "2b7c8d9e-0f1a-4b2c-9d3e-4f5a6b7c8d9e",
+			"name":             "Bypass office",
+			"application_id":   "7d3a9c1c-7a9e-4c57-8b5d-6b1c7f3e2a10",
+			"application_name": "Wiki",
+			"account_id":       "01a7362d577a6c3019a474fd6f485823",
+			"decision":         "bypass",
+			"precedence":       1,
+		},
+		{
+			"id":               "f1a8b3c9-4c5e-4a1e-8d2a-0b9a7b6c5d4e",
+			"name":             "Allow engineers",
+			"application_id":   "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+			"application_name": "Admin Site",
+			"account_id":       "01a7362d577a6c3019a474fd6f485823",
+			"decision":         "allow",
+		},
+	})
+}
+
+func TestAccessPolicyAccessDisabled(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "access_disabled")
+	rows := h.mustQuery("cloudflare_access_policy", []string{"id"})
+
+	assertRows(t, rows, "id", nil)
+}
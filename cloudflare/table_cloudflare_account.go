@@ -20,9 +20,11 @@ func tableCloudflareAccount(ctx context.Context) *plugin.Table {
 			Hydrate: listAccount,
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns:        plugin.SingleColumn("id"),
-			Hydrate:           getAccount,
-			ShouldIgnoreError: isNotFoundError([]string{"HTTP status 404"}),
+			KeyColumns: plugin.SingleColumn("id"),
+			Hydrate:    getAccount,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -59,6 +61,17 @@ func listAccount(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData
 	iter := conn.Accounts.ListAutoPaging(ctx, input)
 	for iter.Next() {
 		account := iter.Current()
+
+		// Skip accounts excluded by the connection's accounts option
+		inScope, err := accountInScope(d, account.ID, account.Name)
+		if err != nil {
+			logger.Error("cloudflare_account.listAccount", "scope error", err)
+			return nil, err
+		}
+		if !inScope {
+			continue
+		}
+
 		d.StreamListItem(ctx, account)
 
 		// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -92,6 +105,12 @@ func getAccount(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 		logger.Error("cloudflare_account.getAccount", "Account api error", err)
 		return nil, err
 	}
+
+	// Accounts outside the connection's scope are not returned, as in the list
+	ok, err := accountInScope(d, account.ID, account.Name)
+	if err != nil || !ok {
+		return nil, err
+	}
 	return account, nil
 }
 
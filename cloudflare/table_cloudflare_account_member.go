@@ -32,9 +32,11 @@ func tableCloudflareAccountMember(ctx context.Context) *plugin.Table {
 			ParentHydrate: listAccount,
 		},
 		Get: &plugin.GetConfig{
-			Hydrate:           getAccountMember,
-			KeyColumns:        plugin.AllColumns([]string{"account_id", "id"}),
-			ShouldIgnoreError: isNotFoundError([]string{"HTTP status 403", "HTTP status 404"}),
+			Hydrate:    getAccountMember,
+			KeyColumns: plugin.AllColumns([]string{"account_id", "id"}),
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError, isForbiddenError),
+			},
 		},
 		Columns: commonColumns([]*plugin.Column{
 			{
@@ -0,0 +1,23 @@
+package cloudflare
+
+import "testing"
+
+func TestAccountMemberList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "account_member")
+	rows := h.mustQuery("cloudflare_account_member", []string{"id", "account_id", "user_email", "status", "title", "roles"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "4536bcfad5faccb111b47003c79917fa", "account_id": "01a7362d577a6c3019a474fd6f485823", "user_email": "pam@example.com", "status": "accepted"},
+		{"id": "9a7806061c88ada191ed06f989cc3dac", "user_email": "dwight@example.com", "status": "pending", "roles": []any{}},
+	})
+}
+
+func TestAccountMemberGet(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "account_member")
+	rows := h.mustQuery("cloudflare_account_member", []string{"id", "user_email"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("id", "4536bcfad5faccb111b47003c79917fa"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "4536bcfad5faccb111b47003c79917fa", "user_email": "pam@example.com"},
+	})
+}
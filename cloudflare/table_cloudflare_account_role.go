@@ -33,9 +33,11 @@ func tableCloudflareAccountRole(ctx context.Context) *plugin.Table {
 			},
 		},
 		Get: &plugin.GetConfig{
-			Hydrate:           getAccountRole,
-			KeyColumns:        plugin.AllColumns([]string{"account_id", "id"}),
-			ShouldIgnoreError: isNotFoundError([]string{"HTTP status 403"}),
+			Hydrate:    getAccountRole,
+			KeyColumns: plugin.AllColumns([]string{"account_id", "id"}),
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isForbiddenError),
+			},
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -0,0 +1,33 @@
+package cloudflare
+
+import "testing"
+
+func TestAccountRoleList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "account_role")
+	rows := h.mustQuery("cloudflare_account_role", []string{"id", "name", "account_id", "description", "permissions", "title"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "05784afa30c1afe1440e79d9351c7430", "name": "Administrator Read Only", "title": "Administrator Read Only", "account_id": "01a7362d577a6c3019a474fd6f485823"},
+		{"id": "3536bcfad5faccb999b47003c79917fb", "name": "Administrator", "description": "Administrative access to the entire Account"},
+	})
+}
+
+func TestAccountRoleListOtherAccount(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "account_role")
+	rows := h.mustQuery("cloudflare_account_role", []string{"id"}, eq("account_id", "00000000000000000000000000000000"))
+
+	assertRows(t, rows, "id", nil)
+	if n := h.server.requestCount("GET", "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/roles"); n != 0 {
+		t.Errorf("roles of a non-matching account should not be listed, made %d calls", n)
+	}
+}
+
+func TestAccountRoleGet(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "account_role")
+	rows := h.mustQuery("cloudflare_account_role", []string{"id", "name", "account_id"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("id", "05784afa30c1afe1440e79d9351c7430"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "05784afa30c1afe1440e79d9351c7430", "name": "Administrator Read Only", "account_id": "01a7362d577a6c3019a474fd6f485823"},
+	})
+}
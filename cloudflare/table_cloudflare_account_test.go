@@ -0,0 +1,70 @@
+package cloudflare
+
+import "testing"
+
+func TestAccountList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts")
+	rows := h.mustQuery("cloudflare_account", []string{"id", "name", "type", "created_on", "settings"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "01a7362d577a6c3019a474fd6f485823", "name": "Example Account", "type": "standard", "created_on": "2023-05-01T10:00:00Z"},
+	})
+	assertValue(t, "settings.enforce_twofactor", rows[0]["settings"].(map[string]any)["enforce_twofactor"], true)
+}
+
+func TestAccountGet(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts")
+	rows := h.mustQuery("cloudflare_account", []string{"id", "name"}, eq("id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "01a7362d577a6c3019a474fd6f485823", "name": "Example Account"},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/accounts"); n != 0 {
+		t.Errorf("get should not list accounts, made %d list calls", n)
+	}
+}
+
+func TestAccountListScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `accounts = ["Example *"]`, "accounts")
+	rows := h.mustQuery("cloudflare_account", []string{"id"})
+	assertRows(t, rows, "id", []map[string]any{{"id": "01a7362d577a6c3019a474fd6f485823"}})
+
+	h = newReplayHarness(t, `accounts = ["00000000000000000000000000000000"]`, "accounts", "account_role")
+	rows = h.mustQuery("cloudflare_account_role", []string{"id"})
+	assertRows(t, rows, "id", nil)
+}
+
+func TestAccountGetScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `accounts = ["Some Other Account"]`, "accounts")
+	rows := h.mustQuery("cloudflare_account", []string{"id"}, eq("id", "01a7362d577a6c3019a474fd6f485823"))
+	assertRows(t, rows, "id", nil)
+}
+
+func TestAccountGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts")
+	rows := h.mustQuery("cloudflare_account", []string{"id"}, eq("id", "00000000000000000000000000000000"))
+
+	assertRows(t, rows, "id", nil)
+}
+
+func TestAccountListIgnoreErrorCodes(t *testing.T) {
+	forbidden := fixtureInteraction{
+		Path:   "/client/v4/accounts",
+		Status: 403,
+		Body:   []byte(`{"success":false,"errors":[{"code":9109,"message":"Unauthorized to access requested resource"}],"messages":[],"result":null}`),
+	}
+
+	// Listing accounts is the top level call, so a permission error fails the query by default
+	h := newReplayHarness(t, "", "accounts")
+	h.server.handle(forbidden)
+	if _, err := h.query("cloudflare_account", []string{"id"}, nil, 0); err == nil {
+		t.Error("expected the permission error to fail the query")
+	}
+
+	for _, codes := range []string{`["9109"]`, `["403"]`, `["4*"]`} {
+		h = newReplayHarness(t, "ignore_error_codes = "+codes, "accounts")
+		h.server.handle(forbidden)
+		rows := h.mustQuery("cloudflare_account", []string{"id"})
+		assertRows(t, rows, "id", nil)
+	}
+}
@@ -0,0 +1,19 @@
+package cloudflare
+
+import "testing"
+
+func TestAPITokenList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "api_token")
+	rows := h.mustQuery("cloudflare_api_token", []string{"id", "name", "status", "issued_on", "expires_on", "condition", "policies"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":         "ed17574386854bf78a67040be0a770b0",
+			"name":       "readonly token",
+			"status":     "active",
+			"issued_on":  "2024-01-01T05:20:00Z",
+			"expires_on": "2025-01-01T00:00:00Z",
+			"condition":  map[string]any{"request_ip": map[string]any{"in": []any{"198.51.100.4/32"}, "not_in": []any{}}},
+		},
+	})
+}
@@ -3,7 +3,6 @@ package cloudflare
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/custom_certificates"
@@ -31,8 +30,10 @@ func tableCloudflareCustomCertificate(ctx context.Context) *plugin.Table {
 				{Name: "id", Require: plugin.Required},
 				{Name: "zone_id", Require: plugin.Required},
 			},
-			ShouldIgnoreError: isNotFoundError([]string{"Invalid custom certificate identifier"}),
-			Hydrate:           getCustomCertificate,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getCustomCertificate,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -95,7 +96,7 @@ func listCustomCertificates(ctx context.Context, d *plugin.QueryData, h *plugin.
 	}
 	if err := iter.Err(); err != nil {
 		// Custom certificates are not available for all plan levels.
-		if strings.Contains(err.Error(), "Plan level does not allow custom certificates") {
+		if isForbiddenError(err) {
 			return nil, nil
 		}
 		logger.Error("cloudflare_custom_certificate.listCustomCertificates", "api_error", err)
@@ -0,0 +1,30 @@
+package cloudflare
+
+import "testing"
+
+func TestCustomCertificateList(t *testing.T) {
+	// The second zone's plan does not allow custom certificates, which is
+	// skipped rather than failing the query
+	h := newReplayHarness(t, "", "zones", "custom_certificate")
+	rows := h.mustQuery("cloudflare_custom_certificate", []string{"id", "zone_id", "bundle_method", "issuer", "priority", "status", "expires_on", "geo_restrictions"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":            "2458ce5a-0c35-4c7f-82c7-8e9487d3ff60",
+			"zone_id":       "023e105f4ecef8ad9ca31a8372d0c353",
+			"bundle_method": "ubiquitous",
+			"issuer":        "GlobalSign",
+			"priority":      "1",
+			"status":        "active",
+			"expires_on":    "2026-01-01T05:20:00Z",
+		},
+	})
+}
+
+func TestCustomCertificateGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "custom_certificate")
+	rows := h.mustQuery("cloudflare_custom_certificate", []string{"id"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "ffffffff-ffff-ffff-ffff-ffffffffffff"))
+
+	assertRows(t, rows, "id", nil)
+}
@@ -29,8 +29,10 @@ func tableCloudflareCustomPage(ctx context.Context) *plugin.Table {
 				{Name: "account_id", Require: plugin.AnyOf},
 				{Name: "zone_id", Require: plugin.AnyOf},
 			},
-			ShouldIgnoreError: isNotFoundError([]string{"Invalid custom page identifier"}),
-			Hydrate:           getCustomPage,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getCustomPage,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -0,0 +1,31 @@
+package cloudflare
+
+import "testing"
+
+func TestCustomPageListByAccount(t *testing.T) {
+	h := newReplayHarness(t, "", "custom_page")
+	rows := h.mustQuery("cloudflare_custom_page", []string{"id", "account_id", "zone_id", "description", "state", "url", "required_tokens"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "basic_challenge", "account_id": "01a7362d577a6c3019a474fd6f485823", "zone_id": nil, "state": "default", "required_tokens": []any{"::CAPTCHA_BOX::"}},
+		{"id": "waf_block", "description": "WAF Block", "state": "customized", "url": "https://example.com/block.html"},
+	})
+}
+
+func TestCustomPageGet(t *testing.T) {
+	h := newReplayHarness(t, "", "custom_page")
+	rows := h.mustQuery("cloudflare_custom_page", []string{"id", "zone_id", "state"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "waf_block"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "waf_block", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "state": "customized"},
+	})
+}
+
+func TestCustomPageGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "custom_page")
+	rows := h.mustQuery("cloudflare_custom_page", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "nope"))
+
+	assertRows(t, rows, "id", nil)
+}
@@ -0,0 +1,505 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/load_balancers"
+	"github.com/cloudflare/cloudflare-go/v4/option"
+	"github.com/cloudflare/cloudflare-go/v4/r2"
+	"github.com/cloudflare/cloudflare-go/v4/workers"
+	"github.com/cloudflare/cloudflare-go/v4/zero_trust"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/query_cache"
+)
+
+// The kinds of Cloudflare-hosted target a CNAME record can point at
+const (
+	danglingTargetWorkersDev = "workers_dev"
+	danglingTargetPagesDev   = "pages_dev"
+	danglingTargetR2Dev      = "r2_dev"
+	danglingTargetTunnel     = "tunnel"
+	danglingTargetZone       = "zone"
+)
+
+// danglingInventoryTTL is how long the targets found in an account are
+// cached. It only needs to cover a single query, which checks every zone of
+// an account against the same targets.
+const danglingInventoryTTL = time.Minute
+
+func tableCloudflareDNSDanglingRecord(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_dns_dangling_record",
+		Description: "CNAME records pointing at Cloudflare-hosted targets that no longer exist.",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+				{Name: "target_type", Require: plugin.Optional},
+			},
+			Hydrate:       listDNSDanglingRecords,
+			ParentHydrate: listZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID"), Description: "Zone where the record is defined."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Description: "Name of the zone where the record is defined."},
+			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ID"), Description: "ID of the record."},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Domain name of the record."},
+			{Name: "type", Type: proto.ColumnType_STRING, Description: "Type of the record."},
+			{Name: "content", Type: proto.ColumnType_STRING, Description: "The hostname the record points at."},
+			{Name: "target_type", Type: proto.ColumnType_STRING, Description: "The kind of Cloudflare-hosted target the record points at: workers_dev, pages_dev, r2_dev, tunnel, or zone for a hostname in one of the account's zones."},
+			{Name: "reason", Type: proto.ColumnType_STRING, Description: "Why the target is considered missing."},
+
+			// Other columns
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID"), Description: "ID of the account the zone belongs to, whose targets the record was checked against."},
+			{Name: "proxied", Type: proto.ColumnType_BOOL, Description: "True if the record has Cloudflare's origin protection."},
+			{Name: "modified_on", Type: proto.ColumnType_TIMESTAMP, Description: "When the record was last modified."},
+		}),
+	}
+}
+
+type DNSDanglingRecordInfo struct {
+	ZoneID     string
+	ZoneName   string
+	AccountID  string
+	ID         string
+	Name       string
+	Type       string
+	Content    string
+	TargetType string
+	Reason     string
+	Proxied    bool
+	ModifiedOn time.Time
+}
+
+func listDNSDanglingRecords(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	zone := h.Item.(zones.Zone)
+
+	// Only check zones stated in the input query
+	inputZoneId := d.EqualsQualString("zone_id")
+	if inputZoneId != "" && inputZoneId != zone.ID {
+		return nil, nil
+	}
+
+	logger := plugin.Logger(ctx)
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_dns_dangling_record.listDNSDanglingRecords", "connection error", err)
+		return nil, err
+	}
+
+	input := dns.RecordListParams{
+		ZoneID:  cloudflare.F(zone.ID),
+		PerPage: cloudflare.F(float64(500)),
+		Type:    cloudflare.F(dns.RecordListParamsTypeCNAME),
+	}
+	var records []dns.RecordResponse
+	err = listZoneDNSRecords(ctx, conn, input, func(record dns.RecordResponse) bool {
+		records = append(records, record)
+		return true
+	})
+	if err != nil {
+		logger.Error("cloudflare_dns_dangling_record.listDNSDanglingRecords", "DNSRecords api error", err)
+		return nil, err
+	}
+
+	checker := &danglingTargetChecker{conn: conn, d: d, accountID: zone.Account.ID}
+	inputTargetType := d.EqualsQualString("target_type")
+	for _, record := range records {
+		target := normalizeHostname(record.Content)
+		targetType, err := checker.targetType(ctx, target)
+		if err != nil {
+			logger.Error("cloudflare_dns_dangling_record.listDNSDanglingRecords", "zones api error", err)
+			return nil, err
+		}
+		if targetType == "" || (inputTargetType != "" && inputTargetType != targetType) {
+			continue
+		}
+
+		reason, err := checker.missingReason(ctx, targetType, target)
+		if err != nil {
+			// Without permission to list a kind of target there is no telling
+			// whether it exists, so the record is not reported
+			if isForbiddenError(err) {
+				logger.Warn("cloudflare_dns_dangling_record.listDNSDanglingRecords", "target_type", targetType, "ignoring permission error", err)
+				continue
+			}
+			logger.Error("cloudflare_dns_dangling_record.listDNSDanglingRecords", "target_type", targetType, "api error", err)
+			return nil, err
+		}
+		if reason == "" {
+			continue
+		}
+
+		d.StreamListItem(ctx, DNSDanglingRecordInfo{
+			ZoneID:     zone.ID,
+			ZoneName:   zone.Name,
+			AccountID:  zone.Account.ID,
+			ID:         record.ID,
+			Name:       record.Name,
+			Type:       string(record.Type),
+			Content:    record.Content,
+			TargetType: targetType,
+			Reason:     reason,
+			Proxied:    record.Proxied,
+			ModifiedOn: record.ModifiedOn,
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// normalizeHostname lower cases a hostname and drops any trailing dot.
+func normalizeHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+}
+
+// hostedTargetType returns the kind of Cloudflare-hosted target a hostname
+// is under by its suffix, or an empty string.
+func hostedTargetType(target string) string {
+	switch {
+	case strings.HasSuffix(target, ".workers.dev"):
+		return danglingTargetWorkersDev
+	case strings.HasSuffix(target, ".pages.dev"):
+		return danglingTargetPagesDev
+	case strings.HasSuffix(target, ".r2.dev"):
+		return danglingTargetR2Dev
+	case strings.HasSuffix(target, ".cfargotunnel.com"):
+		return danglingTargetTunnel
+	}
+	return ""
+}
+
+// danglingTargetChecker looks up the targets of one account, fetching each
+// kind of target only when a record points at it.
+type danglingTargetChecker struct {
+	conn      *cloudflare.Client
+	d         *plugin.QueryData
+	accountID string
+}
+
+// targetType returns the kind of Cloudflare-hosted target a record points
+// at, or an empty string for targets outside Cloudflare and the account.
+func (c *danglingTargetChecker) targetType(ctx context.Context, target string) (string, error) {
+	if targetType := hostedTargetType(target); targetType != "" {
+		return targetType, nil
+	}
+
+	zoneNames, err := c.zoneNames(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := zoneForHostname(zoneNames, target); ok {
+		return danglingTargetZone, nil
+	}
+	return "", nil
+}
+
+// missingReason returns why a target does not exist, or an empty string if
+// it does.
+func (c *danglingTargetChecker) missingReason(ctx context.Context, targetType string, target string) (string, error) {
+	switch targetType {
+	case danglingTargetWorkersDev:
+		return c.missingWorkersDevReason(ctx, target)
+	case danglingTargetPagesDev:
+		return c.missingPagesDevReason(ctx, target)
+	case danglingTargetR2Dev:
+		return c.missingR2DevReason(ctx, target)
+	case danglingTargetTunnel:
+		return c.missingTunnelReason(ctx, target)
+	case danglingTargetZone:
+		return c.missingZoneHostnameReason(ctx, target)
+	}
+	return "", nil
+}
+
+// missingWorkersDevReason checks a <script>.<subdomain>.workers.dev target
+// against the account's workers.dev subdomain and Worker scripts.
+func (c *danglingTargetChecker) missingWorkersDevReason(ctx context.Context, target string) (string, error) {
+	labels := strings.Split(strings.TrimSuffix(target, ".workers.dev"), ".")
+	subdomain := labels[len(labels)-1]
+
+	accountSubdomain, err := cachedAccountTargets(ctx, c.d, "workers-subdomain-"+c.accountID, func() (string, error) {
+		res, err := c.conn.Workers.Subdomains.Get(ctx, workers.SubdomainGetParams{AccountID: cloudflare.F(c.accountID)})
+		if err != nil {
+			if isNotFoundError(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		return strings.ToLower(res.Subdomain), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	// A subdomain other than the account's own may belong to another
+	// account, whose scripts cannot be seen, so the target is not reported
+	if subdomain != accountSubdomain {
+		plugin.Logger(ctx).Debug("cloudflare_dns_dangling_record.missingWorkersDevReason", "target", target, "unverifiable subdomain", subdomain)
+		return "", nil
+	}
+	if len(labels) == 1 {
+		return "", nil
+	}
+
+	scripts, err := cachedAccountTargets(ctx, c.d, "worker-scripts-"+c.accountID, func() (map[string]bool, error) {
+		scripts := map[string]bool{}
+		iter := c.conn.Workers.Scripts.ListAutoPaging(ctx, workers.ScriptListParams{AccountID: cloudflare.F(c.accountID)})
+		for iter.Next() {
+			scripts[strings.ToLower(iter.Current().ID)] = true
+		}
+		return scripts, iter.Err()
+	})
+	if err != nil {
+		return "", err
+	}
+	script := labels[len(labels)-2]
+	if !scripts[script] {
+		return fmt.Sprintf("No Worker script named %s", script), nil
+	}
+	return "", nil
+}
+
+// pagesProject is a Pages project as listed by the API. The SDK decodes
+// projects as deployments, which have neither of these fields.
+type pagesProject struct {
+	Name      string `json:"name"`
+	Subdomain string `json:"subdomain"`
+}
+
+// missingPagesDevReason checks a pages.dev target, which may be a project's
+// subdomain or a branch or deployment under it, against the account's Pages
+// projects.
+func (c *danglingTargetChecker) missingPagesDevReason(ctx context.Context, target string) (string, error) {
+	labels := strings.Split(strings.TrimSuffix(target, ".pages.dev"), ".")
+	projectSubdomain := labels[len(labels)-1] + ".pages.dev"
+
+	subdomains, err := cachedAccountTargets(ctx, c.d, "pages-projects-"+c.accountID, func() (map[string]bool, error) {
+		subdomains := map[string]bool{}
+		path := fmt.Sprintf("accounts/%s/pages/projects", c.accountID)
+		for page := 1; ; page++ {
+			var res struct {
+				Result     []pagesProject `json:"result"`
+				ResultInfo struct {
+					TotalPages int `json:"total_pages"`
+				} `json:"result_info"`
+			}
+			if err := c.conn.Get(ctx, path, nil, &res, option.WithQuery("page", strconv.Itoa(page))); err != nil {
+				return nil, err
+			}
+			for _, project := range res.Result {
+				subdomains[normalizeHostname(project.Subdomain)] = true
+			}
+			if len(res.Result) == 0 || page >= res.ResultInfo.TotalPages {
+				return subdomains, nil
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	if !subdomains[projectSubdomain] {
+		return fmt.Sprintf("No Pages project with the subdomain %s", projectSubdomain), nil
+	}
+	return "", nil
+}
+
+// r2ManagedDomain is the r2.dev URL of a bucket.
+type r2ManagedDomain struct {
+	Bucket  string
+	Enabled bool
+}
+
+// missingR2DevReason checks a pub-<id>.r2.dev target against the r2.dev URLs
+// of the account's buckets.
+func (c *danglingTargetChecker) missingR2DevReason(ctx context.Context, target string) (string, error) {
+	domains, err := cachedAccountTargets(ctx, c.d, "r2-managed-domains-"+c.accountID, func() (map[string]r2ManagedDomain, error) {
+		domains := map[string]r2ManagedDomain{}
+		for _, jurisdiction := range r2Jurisdictions {
+			input := r2.BucketListParams{
+				AccountID:    cloudflare.F(c.accountID),
+				PerPage:      cloudflare.F(float64(1000)),
+				Jurisdiction: cloudflare.F(r2.BucketListParamsCfR2Jurisdiction(jurisdiction)),
+			}
+			for {
+				var page r2BucketListEnvelope
+				if _, err := c.conn.R2.Buckets.List(ctx, input, option.WithResponseBodyInto(&page)); err != nil {
+					if isR2JurisdictionUnavailable(c.d, jurisdiction, err) {
+						break
+					}
+					return nil, err
+				}
+
+				for _, bucket := range page.Result.Buckets {
+					domainInput := r2.BucketDomainManagedListParams{
+						AccountID:    cloudflare.F(c.accountID),
+						Jurisdiction: cloudflare.F(r2.BucketDomainManagedListParamsCfR2Jurisdiction(jurisdiction)),
+					}
+					domain, err := c.conn.R2.Buckets.Domains.Managed.List(ctx, bucket.Name, domainInput)
+					if err != nil {
+						return nil, err
+					}
+					if domain.Domain != "" {
+						domains[normalizeHostname(domain.Domain)] = r2ManagedDomain{Bucket: bucket.Name, Enabled: domain.Enabled}
+					}
+				}
+
+				if page.ResultInfo.Cursor == "" || len(page.Result.Buckets) == 0 {
+					break
+				}
+				input.Cursor = cloudflare.F(page.ResultInfo.Cursor)
+			}
+		}
+		return domains, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	domain, ok := domains[target]
+	switch {
+	case !ok:
+		return "No R2 bucket with this r2.dev URL", nil
+	case !domain.Enabled:
+		return fmt.Sprintf("The r2.dev URL of bucket %s is disabled", domain.Bucket), nil
+	}
+	return "", nil
+}
+
+// missingTunnelReason checks a <tunnel id>.cfargotunnel.com target against
+// the account's tunnels that have not been deleted.
+func (c *danglingTargetChecker) missingTunnelReason(ctx context.Context, target string) (string, error) {
+	tunnels, err := cachedAccountTargets(ctx, c.d, "tunnels-"+c.accountID, func() (map[string]bool, error) {
+		tunnels := map[string]bool{}
+		input := zero_trust.TunnelListParams{
+			AccountID: cloudflare.F(c.accountID),
+			IsDeleted: cloudflare.F(false),
+		}
+		iter := c.conn.ZeroTrust.Tunnels.ListAutoPaging(ctx, input)
+		for iter.Next() {
+			tunnels[strings.ToLower(iter.Current().ID)] = true
+		}
+		return tunnels, iter.Err()
+	})
+	if err != nil {
+		return "", err
+	}
+
+	tunnelID := strings.TrimSuffix(target, ".cfargotunnel.com")
+	if !tunnels[tunnelID] {
+		return fmt.Sprintf("No Cloudflare Tunnel with the ID %s", tunnelID), nil
+	}
+	return "", nil
+}
+
+// missingZoneHostnameReason checks a hostname in one of the account's zones
+// for a DNS record or load balancer of that name.
+func (c *danglingTargetChecker) missingZoneHostnameReason(ctx context.Context, target string) (string, error) {
+	zoneNames, err := c.zoneNames(ctx)
+	if err != nil {
+		return "", err
+	}
+	zoneName, _ := zoneForHostname(zoneNames, target)
+	zoneID := zoneNames[zoneName]
+
+	recordNames, err := c.zoneRecordNames(ctx, zoneID)
+	if err != nil {
+		return "", err
+	}
+	loadBalancers, err := cachedAccountTargets(ctx, c.d, "load-balancers-"+zoneID, func() (map[string]bool, error) {
+		loadBalancers := map[string]bool{}
+		iter := c.conn.LoadBalancers.ListAutoPaging(ctx, load_balancers.LoadBalancerListParams{ZoneID: cloudflare.F(zoneID)})
+		for iter.Next() {
+			loadBalancers[normalizeHostname(iter.Current().Name)] = true
+		}
+		return loadBalancers, iter.Err()
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// A wildcard owner in any parent up to the zone apex also answers for the
+	// target, the closest parent first
+	names := []string{target}
+	for name := target; name != zoneName; {
+		_, name, _ = strings.Cut(name, ".")
+		names = append(names, "*."+name)
+	}
+	for _, name := range names {
+		if loadBalancers[name] || recordNames[name] {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("No DNS record or load balancer named %s in zone %s", target, zoneName), nil
+}
+
+// zoneRecordNames returns the names of the DNS records in a zone. The zone is
+// listed once for the query, however many records point into it.
+func (c *danglingTargetChecker) zoneRecordNames(ctx context.Context, zoneID string) (map[string]bool, error) {
+	return cachedAccountTargets(ctx, c.d, "record-names-"+zoneID, func() (map[string]bool, error) {
+		recordNames := map[string]bool{}
+		input := dns.RecordListParams{
+			ZoneID:  cloudflare.F(zoneID),
+			PerPage: cloudflare.F(float64(500)),
+		}
+		err := listZoneDNSRecords(ctx, c.conn, input, func(record dns.RecordResponse) bool {
+			recordNames[normalizeHostname(record.Name)] = true
+			return true
+		})
+		return recordNames, err
+	})
+}
+
+// zoneNames maps the names of the account's zones to their IDs.
+func (c *danglingTargetChecker) zoneNames(ctx context.Context) (map[string]string, error) {
+	return cachedAccountTargets(ctx, c.d, "zone-names-"+c.accountID, func() (map[string]string, error) {
+		zoneNames := map[string]string{}
+		input := zones.ZoneListParams{
+			Account: cloudflare.F(zones.ZoneListParamsAccount{ID: cloudflare.F(c.accountID)}),
+			PerPage: cloudflare.F(float64(500)),
+		}
+		iter := c.conn.Zones.ListAutoPaging(ctx, input)
+		for iter.Next() {
+			zone := iter.Current()
+			zoneNames[normalizeHostname(zone.Name)] = zone.ID
+		}
+		return zoneNames, iter.Err()
+	})
+}
+
+// zoneForHostname returns the name of the most specific zone a hostname is
+// in.
+func zoneForHostname(zoneNames map[string]string, hostname string) (string, bool) {
+	for name := hostname; name != ""; {
+		if _, ok := zoneNames[name]; ok {
+			return name, true
+		}
+		_, parent, found := strings.Cut(name, ".")
+		if !found {
+			break
+		}
+		name = parent
+	}
+	return "", false
+}
+
+// cachedAccountTargets caches the result of fetch for the connection for
+// danglingInventoryTTL, so the zones of an account share its targets. Zones
+// are listed in parallel, so the first zone to need a kind of target fetches
+// it while the others wait.
+func cachedAccountTargets[T any](ctx context.Context, d *plugin.QueryData, key string, fetch func() (T, error)) (T, error) {
+	return callOncePerConnection(ctx, d, "dangling-"+key, danglingInventoryTTL, fetch)
+}
@@ -0,0 +1,89 @@
+package cloudflare
+
+import "testing"
+
+func TestDNSDanglingRecordList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_dangling_record")
+	rows := h.mustQuery("cloudflare_dns_dangling_record", []string{"name", "zone_id", "account_id", "content", "target_type", "reason"})
+
+	// api.example.com points at a name covered by the *.gw.example.com
+	// wildcard, and team.example.com at another account's workers.dev
+	// subdomain, which cannot be checked
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "blog.example.com", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "account_id": "01a7362d577a6c3019a474fd6f485823", "content": "old-blog.pages.dev", "target_type": "pages_dev", "reason": "No Pages project with the subdomain old-blog.pages.dev"},
+		{"name": "files.example.com", "target_type": "r2_dev", "reason": "The r2.dev URL of bucket logs is disabled"},
+		{"name": "media.example.com", "target_type": "r2_dev", "reason": "No R2 bucket with this r2.dev URL"},
+		{"name": "old-api.example.com", "target_type": "workers_dev", "reason": "No Worker script named old-api"},
+		{"name": "old-tunnel.example.com", "target_type": "tunnel", "reason": "No Cloudflare Tunnel with the ID 0d3a2c5e-4b1f-4e7a-9c8d-6f5e4d3c2b1a"},
+		{"name": "shop.example.com", "content": "shop-lb.example.com.", "target_type": "zone", "reason": "No DNS record or load balancer named shop-lb.example.com in zone example.com"},
+	})
+
+	// Each kind of target is only listed once for the account
+	for _, path := range []string{
+		"/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/workers/scripts",
+		"/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/pages/projects",
+		"/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/r2/buckets/logs/domains/managed",
+	} {
+		if n := h.server.requestCount("GET", path); n != 1 {
+			t.Errorf("%s: made %d calls, want 1", path, n)
+		}
+	}
+	// A zone's records are listed once for their CNAMEs and once for the
+	// names that CNAMEs into the zone are checked against, each listing
+	// ending on an empty second page
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records"); n != 4 {
+		t.Errorf("made %d calls listing the example.com records, want 4", n)
+	}
+}
+
+func TestDNSDanglingRecordListByTargetType(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_dangling_record")
+	rows := h.mustQuery("cloudflare_dns_dangling_record", []string{"name", "target_type"}, eq("target_type", "tunnel"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "old-tunnel.example.com", "target_type": "tunnel"},
+	})
+	// Targets of other kinds are not looked up
+	for _, path := range []string{
+		"/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/workers/subdomain",
+		"/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/pages/projects",
+		"/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/r2/buckets",
+	} {
+		if n := h.server.requestCount("GET", path); n != 0 {
+			t.Errorf("%s: made %d calls, want 0", path, n)
+		}
+	}
+}
+
+func TestDNSDanglingRecordSkipsForbiddenTargets(t *testing.T) {
+	// Without permission to list tunnels, tunnel targets cannot be checked
+	h := newReplayHarness(t, "", "zones", "dns_dangling_record")
+	h.server.handle(fixtureInteraction{
+		Path:   "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/tunnels",
+		Status: 403,
+		Body:   []byte(`{"success":false,"errors":[{"code":10000,"message":"Authentication error"}],"messages":[],"result":null}`),
+	})
+	rows := h.mustQuery("cloudflare_dns_dangling_record", []string{"name"}, eq("target_type", "tunnel"))
+
+	assertRows(t, rows, "name", nil)
+}
+
+func TestZoneForHostname(t *testing.T) {
+	zoneNames := map[string]string{"example.com": "1", "sub.example.com": "2"}
+	tests := []struct {
+		hostname, zone string
+		ok             bool
+	}{
+		{"example.com", "example.com", true},
+		{"www.example.com", "example.com", true},
+		{"a.sub.example.com", "sub.example.com", true},
+		{"example.org", "", false},
+		{"notexample.com", "", false},
+	}
+	for _, tt := range tests {
+		zone, ok := zoneForHostname(zoneNames, tt.hostname)
+		if zone != tt.zone || ok != tt.ok {
+			t.Errorf("zoneForHostname(%q) = %q, %v, want %q, %v", tt.hostname, zone, ok, tt.zone, tt.ok)
+		}
+	}
+}
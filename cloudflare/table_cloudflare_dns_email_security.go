@@ -0,0 +1,157 @@
+package cloudflare
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/query_cache"
+)
+
+func tableCloudflareDNSEmailSecurity(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_dns_email_security",
+		Description: "SPF, DMARC, DKIM and MTA-STS records of a zone, parsed from its TXT records.",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+			},
+			Hydrate:       listDNSEmailSecurity,
+			ParentHydrate: listZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID"), Description: "ID of the zone."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneName"), Description: "Name of the zone."},
+			{Name: "findings", Type: proto.ColumnType_JSON, Transform: transform.FromField("Findings"), Description: "Problems found with the zone's email authentication records, such as multiple SPF records or a DMARC policy of p=none."},
+
+			// SPF columns
+			{Name: "spf_record", Type: proto.ColumnType_STRING, Transform: transform.FromField("SPFRecord").NullIfZero(), Description: "The SPF record of the zone. If there are several, the first."},
+			{Name: "spf_record_count", Type: proto.ColumnType_INT, Transform: transform.FromField("SPFRecordCount"), Description: "The number of SPF records of the zone. More than one makes SPF checks fail."},
+			{Name: "spf_mechanisms", Type: proto.ColumnType_JSON, Transform: transform.FromField("SPF.Terms"), Description: "The mechanisms and modifiers of the SPF record, each with its qualifier, mechanism and value."},
+			{Name: "spf_all_qualifier", Type: proto.ColumnType_STRING, Transform: transform.FromField("SPF.AllQualifier").NullIfZero(), Description: "The result of the SPF record's all mechanism: pass, fail, softfail or neutral."},
+			{Name: "spf_redirect", Type: proto.ColumnType_STRING, Transform: transform.FromField("SPF.Redirect").NullIfZero(), Description: "The domain whose SPF record the redirect modifier points to."},
+			{Name: "spf_lookup_count", Type: proto.ColumnType_INT, Transform: transform.FromField("SPF.LookupCount"), Description: "The number of DNS lookups the SPF record's own mechanisms and modifiers make, not counting those of included records. SPF allows at most 10."},
+
+			// DMARC columns
+			{Name: "dmarc_record", Type: proto.ColumnType_STRING, Transform: transform.FromField("DMARCRecord").NullIfZero(), Description: "The DMARC record of the zone. If there are several, the first."},
+			{Name: "dmarc_record_count", Type: proto.ColumnType_INT, Transform: transform.FromField("DMARCRecordCount"), Description: "The number of DMARC records of the zone. More than one makes DMARC checks fail."},
+			{Name: "dmarc_policy", Type: proto.ColumnType_STRING, Transform: transform.FromField("DMARC.Policy").NullIfZero(), Description: "The DMARC policy for the domain: none, quarantine or reject."},
+			{Name: "dmarc_subdomain_policy", Type: proto.ColumnType_STRING, Transform: transform.FromField("DMARC.SubdomainPolicy").NullIfZero(), Description: "The DMARC policy for subdomains, which defaults to the domain's policy."},
+			{Name: "dmarc_pct", Type: proto.ColumnType_INT, Transform: transform.FromField("DMARC.Pct"), Description: "The percentage of failing mail the DMARC policy applies to. Null when the record has no pct tag, in which case the policy applies to all mail."},
+			{Name: "dmarc_rua", Type: proto.ColumnType_JSON, Transform: transform.FromField("DMARC.Rua"), Description: "The addresses aggregate DMARC reports are sent to."},
+			{Name: "dmarc_ruf", Type: proto.ColumnType_JSON, Transform: transform.FromField("DMARC.Ruf"), Description: "The addresses failure DMARC reports are sent to."},
+			{Name: "dmarc_tags", Type: proto.ColumnType_JSON, Transform: transform.FromField("DMARC.Tags"), Description: "All the tags of the DMARC record."},
+
+			// DKIM and MTA-STS columns
+			{Name: "dkim_records", Type: proto.ColumnType_JSON, Transform: transform.FromField("DKIMRecords"), Description: "The DKIM public keys published as TXT records under _domainkey, each with its selector, name, key type and whether it is revoked or in testing mode."},
+			{Name: "mta_sts_record", Type: proto.ColumnType_STRING, Transform: transform.FromField("MTASTSRecord").NullIfZero(), Description: "The _mta-sts record of the zone, which announces an MTA-STS policy."},
+			{Name: "mta_sts_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("MTASTSID").NullIfZero(), Description: "The ID of the MTA-STS policy, which changes whenever the policy does."},
+		}),
+	}
+}
+
+type DNSEmailSecurityInfo struct {
+	ZoneID           string
+	ZoneName         string
+	Findings         []string
+	SPFRecord        string
+	SPFRecordCount   int
+	SPF              *spfRecord
+	DMARCRecord      string
+	DMARCRecordCount int
+	DMARC            *dmarcRecord
+	DKIMRecords      []dkimRecord
+	MTASTSRecord     string
+	MTASTSID         string
+}
+
+func listDNSEmailSecurity(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	zone := h.Item.(zones.Zone)
+
+	// Only check zones stated in the input query
+	inputZoneId := d.EqualsQualString("zone_id")
+	if inputZoneId != "" && inputZoneId != zone.ID {
+		return nil, nil
+	}
+
+	logger := plugin.Logger(ctx)
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_dns_email_security.listDNSEmailSecurity", "connection error", err)
+		return nil, err
+	}
+
+	input := dns.RecordListParams{
+		ZoneID:  cloudflare.F(zone.ID),
+		PerPage: cloudflare.F(float64(500)),
+		Type:    cloudflare.F(dns.RecordListParamsTypeTXT),
+	}
+	var records []dns.RecordResponse
+	err = listZoneDNSRecords(ctx, conn, input, func(record dns.RecordResponse) bool {
+		records = append(records, record)
+		return true
+	})
+	if err != nil {
+		logger.Error("cloudflare_dns_email_security.listDNSEmailSecurity", "DNSRecords api error", err)
+		return nil, err
+	}
+
+	d.StreamListItem(ctx, newDNSEmailSecurityInfo(zone, records))
+
+	return nil, nil
+}
+
+// newDNSEmailSecurityInfo picks the email authentication records of the zone
+// out of its TXT records and parses them.
+func newDNSEmailSecurityInfo(zone zones.Zone, records []dns.RecordResponse) DNSEmailSecurityInfo {
+	info := DNSEmailSecurityInfo{
+		ZoneID:   zone.ID,
+		ZoneName: zone.Name,
+	}
+
+	zoneName := strings.ToLower(zone.Name)
+	dkimSuffix := "._domainkey." + zoneName
+	var spf spfRecord
+	var dmarc dmarcRecord
+	mtaSTSCount := 0
+
+	for _, record := range records {
+		name := strings.ToLower(record.Name)
+		value := txtRecordValue(record.Content)
+
+		switch {
+		case name == zoneName && hasRecordVersion(value, "v=spf1"):
+			if info.SPFRecordCount == 0 {
+				info.SPFRecord = value
+				spf = parseSPFRecord(value)
+				info.SPF = &spf
+			}
+			info.SPFRecordCount++
+		case name == "_dmarc."+zoneName && hasRecordVersion(value, "v=DMARC1"):
+			if info.DMARCRecordCount == 0 {
+				info.DMARCRecord = value
+				dmarc = parseDMARCRecord(value)
+				info.DMARC = &dmarc
+			}
+			info.DMARCRecordCount++
+		case name == "_mta-sts."+zoneName && hasRecordVersion(value, "v=STSv1"):
+			if mtaSTSCount == 0 {
+				info.MTASTSRecord = value
+				info.MTASTSID = parseTagList(value)["id"]
+			}
+			mtaSTSCount++
+		case strings.HasSuffix(name, dkimSuffix) && len(name) > len(dkimSuffix):
+			info.DKIMRecords = append(info.DKIMRecords, parseDKIMRecord(strings.TrimSuffix(name, dkimSuffix), name, value))
+		}
+	}
+
+	info.Findings = emailSecurityFindings(info.SPFRecordCount, spf, info.DMARCRecordCount, dmarc, info.DKIMRecords, mtaSTSCount)
+
+	return info
+}
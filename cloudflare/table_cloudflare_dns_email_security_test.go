@@ -0,0 +1,83 @@
+package cloudflare
+
+import "testing"
+
+func TestDNSEmailSecurityList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_email_security")
+	rows := h.mustQuery("cloudflare_dns_email_security", []string{
+		"zone_id", "zone_name", "spf_record", "spf_record_count", "spf_all_qualifier", "spf_redirect", "spf_lookup_count",
+		"dmarc_policy", "dmarc_subdomain_policy", "dmarc_pct", "dmarc_rua", "dmarc_ruf", "mta_sts_id", "findings",
+	})
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id":                "023e105f4ecef8ad9ca31a8372d0c353",
+			"zone_name":              "example.com",
+			"spf_record":             "v=spf1 ip4:192.0.2.0/24 include:_spf.google.com include:mailgun.org mx -all",
+			"spf_record_count":       1,
+			"spf_all_qualifier":      "fail",
+			"spf_redirect":           nil,
+			"spf_lookup_count":       3,
+			"dmarc_policy":           "reject",
+			"dmarc_subdomain_policy": "quarantine",
+			"dmarc_pct":              nil,
+			"dmarc_rua":              []any{"mailto:dmarc@example.com", "mailto:reports@dmarc.example.net"},
+			"dmarc_ruf":              []any{"mailto:forensics@example.com"},
+			"mta_sts_id":             "20240101T000000",
+			"findings":               []any{"DKIM key for selector old is revoked"},
+		},
+		{
+			"zone_id":                "9a7806061c88ada191ed06f989cc3dac",
+			"zone_name":              "example.org",
+			"spf_record_count":       2,
+			"spf_all_qualifier":      nil,
+			"spf_redirect":           "_spf.example.net",
+			"spf_lookup_count":       11,
+			"dmarc_policy":           "none",
+			"dmarc_subdomain_policy": "none",
+			"dmarc_pct":              50,
+			"dmarc_rua":              nil,
+			"mta_sts_id":             nil,
+			"findings": []any{
+				"2 SPF records, which makes SPF checks fail",
+				"SPF record makes 11 DNS lookups, more than the limit of 10",
+				"DMARC policy is p=none, which only monitors mail",
+				"DMARC policy only applies to 50% of mail",
+				"DMARC record has no rua address for aggregate reports",
+				"DKIM key for selector mail is in testing mode",
+			},
+		},
+	})
+}
+
+func TestDNSEmailSecurityMechanismsAndKeys(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_email_security")
+	rows := h.mustQuery("cloudflare_dns_email_security", []string{"zone_id", "spf_mechanisms", "dkim_records"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id": "023e105f4ecef8ad9ca31a8372d0c353",
+			"spf_mechanisms": []any{
+				map[string]any{"qualifier": "pass", "mechanism": "ip4", "value": "192.0.2.0/24"},
+				map[string]any{"qualifier": "pass", "mechanism": "include", "value": "_spf.google.com"},
+				map[string]any{"qualifier": "pass", "mechanism": "include", "value": "mailgun.org"},
+				map[string]any{"qualifier": "pass", "mechanism": "mx"},
+				map[string]any{"qualifier": "fail", "mechanism": "all"},
+			},
+			"dkim_records": []any{
+				map[string]any{"selector": "google", "name": "google._domainkey.example.com", "key_type": "rsa", "revoked": false, "testing": false},
+				map[string]any{"selector": "old", "name": "old._domainkey.example.com", "key_type": "rsa", "revoked": true, "testing": false},
+			},
+		},
+	})
+
+	// Only TXT records are listed
+	r := h.server.lastRequest("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records")
+	if r == nil {
+		t.Fatal("no request for the zone's records")
+	}
+	assertValue(t, "type filter", r.URL.Query().Get("type"), "TXT")
+	if n := h.server.requestCount("GET", "/client/v4/zones/9a7806061c88ada191ed06f989cc3dac/dns_records"); n != 0 {
+		t.Errorf("records of a non-matching zone should not be listed, made %d calls", n)
+	}
+}
@@ -0,0 +1,92 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/accounts"
+	"github.com/cloudflare/cloudflare-go/v4/dns_firewall"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+)
+
+func tableCloudflareDNSFirewall(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_dns_firewall",
+		Description: "A DNS Firewall cluster proxies and caches DNS queries in front of an account's upstream nameservers.",
+		List: &plugin.ListConfig{
+			Hydrate:       listDNSFirewalls,
+			ParentHydrate: listAccount,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "account_id", Require: plugin.Optional},
+			},
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ID"), Description: "ID of the DNS Firewall cluster."},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Name of the DNS Firewall cluster."},
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID"), Description: "ID of the account the cluster belongs to."},
+			{Name: "modified_on", Type: proto.ColumnType_TIMESTAMP, Description: "When the cluster was last modified."},
+
+			// Other columns
+			{Name: "dns_firewall_ips", Type: proto.ColumnType_JSON, Transform: transform.FromField("DNSFirewallIPs"), Description: "The IP addresses the cluster answers queries on."},
+			{Name: "upstream_ips", Type: proto.ColumnType_JSON, Transform: transform.FromField("UpstreamIPs"), Description: "The IP addresses of the upstream nameservers queries are forwarded to."},
+			{Name: "deprecate_any_requests", Type: proto.ColumnType_BOOL, Description: "True if queries for the ANY type are refused."},
+			{Name: "ecs_fallback", Type: proto.ColumnType_BOOL, Transform: transform.FromField("ECSFallback"), Description: "True if the client's IP address is forwarded as EDNS Client Subnet when the query has none."},
+			{Name: "minimum_cache_ttl", Type: proto.ColumnType_INT, Transform: transform.FromField("MinimumCacheTTL"), Description: "The lowest TTL answers are cached for, in seconds."},
+			{Name: "maximum_cache_ttl", Type: proto.ColumnType_INT, Transform: transform.FromField("MaximumCacheTTL"), Description: "The highest TTL answers are cached for, in seconds."},
+			{Name: "negative_cache_ttl", Type: proto.ColumnType_INT, Transform: transform.FromField("NegativeCacheTTL").NullIfZero(), Description: "How long NXDOMAIN and NODATA answers are cached for, in seconds. Null if the TTL of the SOA record is used."},
+			{Name: "ratelimit", Type: proto.ColumnType_INT, Transform: transform.FromField("Ratelimit").NullIfZero(), Description: "The number of queries per second allowed from each Cloudflare data center to the upstream nameservers. Null if there is no limit."},
+			{Name: "retries", Type: proto.ColumnType_INT, Description: "The number of times a query is retried against the upstream nameservers after a timeout."},
+			{Name: "attack_mitigation", Type: proto.ColumnType_JSON, Description: "Whether random-prefix attacks are mitigated to protect the upstream nameservers, and whether only when they seem unhealthy."},
+		}),
+	}
+}
+
+type DNSFirewallInfo struct {
+	AccountID string
+	dns_firewall.DNSFirewallListResponse
+}
+
+//// LIST FUNCTION
+
+func listDNSFirewalls(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	account := h.Item.(accounts.Account)
+
+	// Only list clusters for accounts stated in the input query
+	inputAccountId := d.EqualsQualString("account_id")
+	if inputAccountId != "" && inputAccountId != account.ID {
+		return nil, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_dns_firewall.listDNSFirewalls", "connection error", err)
+		return nil, err
+	}
+
+	input := dns_firewall.DNSFirewallListParams{
+		AccountID: cloudflare.F(account.ID),
+	}
+
+	iter := conn.DNSFirewall.ListAutoPaging(ctx, input)
+	for iter.Next() {
+		d.StreamListItem(ctx, DNSFirewallInfo{
+			AccountID:               account.ID,
+			DNSFirewallListResponse: iter.Current(),
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+	if err := iter.Err(); err != nil {
+		logger.Error("cloudflare_dns_firewall.listDNSFirewalls", "api error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
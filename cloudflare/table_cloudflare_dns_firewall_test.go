@@ -0,0 +1,36 @@
+package cloudflare
+
+import "testing"
+
+func TestDNSFirewallList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "dns_firewall")
+	rows := h.mustQuery("cloudflare_dns_firewall", []string{
+		"id", "name", "account_id", "modified_on", "dns_firewall_ips", "upstream_ips", "deprecate_any_requests", "ecs_fallback",
+		"minimum_cache_ttl", "maximum_cache_ttl", "negative_cache_ttl", "ratelimit", "retries", "attack_mitigation",
+	})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":                     "023e105f4ecef8ad9ca31a8372d0c353",
+			"name":                   "My Awesome DNS Firewall cluster",
+			"account_id":             "01a7362d577a6c3019a474fd6f485823",
+			"modified_on":            "2024-01-01T05:20:00Z",
+			"dns_firewall_ips":       []any{"203.0.113.1", "203.0.113.254"},
+			"upstream_ips":           []any{"192.0.2.1", "198.51.100.1"},
+			"deprecate_any_requests": true,
+			"ecs_fallback":           false,
+			"minimum_cache_ttl":      60,
+			"maximum_cache_ttl":      900,
+			"ratelimit":              600,
+			"retries":                2,
+			"attack_mitigation":      map[string]any{"enabled": true, "only_when_upstream_unhealthy": false},
+		},
+	})
+}
+
+func TestDNSFirewallListOtherAccount(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "dns_firewall")
+	rows := h.mustQuery("cloudflare_dns_firewall", []string{"id"}, eq("account_id", "00000000000000000000000000000000"))
+
+	assertRows(t, rows, "id", []map[string]any{})
+}
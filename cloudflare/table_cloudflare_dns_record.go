@@ -2,6 +2,8 @@ package cloudflare
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/dns"
@@ -19,19 +21,27 @@ func tableCloudflareDNSRecord(ctx context.Context) *plugin.Table {
 		List: &plugin.ListConfig{
 			KeyColumns: plugin.KeyColumnSlice{
 				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+				{Name: "type", Require: plugin.Optional},
+				{Name: "name", Require: plugin.Optional, Operators: []string{"=", "~~", "~~*"}},
+				{Name: "content", Require: plugin.Optional, Operators: []string{"=", "~~", "~~*"}},
+				{Name: "proxied", Require: plugin.Optional},
+				{Name: "comment", Require: plugin.Optional, Operators: []string{"=", "~~", "~~*"}},
+				{Name: "tag", Require: plugin.Optional},
 			},
 			Hydrate: listDNSRecord,
 			ParentHydrate: listZones,
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns:        plugin.AllColumns([]string{"zone_id", "id"}),
-			ShouldIgnoreError: isNotFoundError([]string{"HTTP status 404"}),
-			Hydrate:           getDNSRecord,
+			KeyColumns: plugin.AllColumns([]string{"zone_id", "id"}),
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getDNSRecord,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
 			{Name: "zone_id", Type: proto.ColumnType_STRING, Description: "Zone where the record is defined.", Transform: transform.FromField("ZoneID")},
-			{Name: "zone_name", Type: proto.ColumnType_STRING, Description: "[Deprecated] Name of the zone where the record is defined."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Hydrate: getDNSRecordZoneName, Transform: transform.FromValue(), Description: "Name of the zone where the record is defined."},
 			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ID"), Description: "ID of the record."},
 			{Name: "type", Type: proto.ColumnType_STRING, Description: "Type of the record (e.g. A, MX, CNAME)."},
 			{Name: "name", Type: proto.ColumnType_STRING, Description: "Domain name for the record (e.g. steampipe.io)."},
@@ -47,16 +57,24 @@ func tableCloudflareDNSRecord(ctx context.Context) *plugin.Table {
 			{Name: "priority", Type: proto.ColumnType_INT, Description: "Priority for this record, primarily used for MX records."},
 			{Name: "proxiable", Type: proto.ColumnType_BOOL, Description: "True if the record is eligible for Cloudflare's origin protection."},
 			{Name: "proxied", Type: proto.ColumnType_BOOL, Description: "True if the record has Cloudflare's origin protection."},
+			{Name: "comment", Type: proto.ColumnType_STRING, Transform: transform.FromField("Comment").NullIfZero(), Description: "Comments or notes about the DNS record."},
+			{Name: "comment_modified_on", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CommentModifiedOn").NullIfZero(), Description: "When the record comment was last modified."},
+			{Name: "tags_modified_on", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("TagsModifiedOn").NullIfZero(), Description: "When the record tags were last modified."},
+			{Name: "tag", Type: proto.ColumnType_STRING, Transform: transform.FromQual("tag"), Description: "Filter records by tag, either a tag name, which must be present, or \"name:value\"."},
 
 			// JSON columns
 			{Name: "data", Type: proto.ColumnType_JSON, Description: "Map of attributes that constitute the record value. Primarily used for LOC and SRV record types."},
 			{Name: "meta", Type: proto.ColumnType_JSON, Description: "Cloudflare metadata for this record."},
+			{Name: "settings", Type: proto.ColumnType_JSON, Description: "Settings for the record, such as flatten_cname for CNAME records and ipv4_only or ipv6_only for proxied records."},
+			{Name: "tags", Type: proto.ColumnType_JSON, Transform: transform.FromField("Tags").Transform(dnsRecordTagsToMap), Description: "Map of the tags on the record, from tag name to value. Tags without a value map to an empty string."},
+			{Name: "tags_src", Type: proto.ColumnType_JSON, Transform: transform.FromField("Tags"), Description: "Custom tags for the record, each a name or \"name:value\"."},
 		}),
 	}
 }
 
 type RecordInfo struct {
-	ZoneID string
+	ZoneID   string
+	ZoneName string
 	dns.RecordResponse
 }
 
@@ -88,23 +106,20 @@ func listDNSRecord(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 		ZoneID:  cloudflare.F(zoneDetails.ID),
 		PerPage: cloudflare.F(float64(maxLimit)),
 	}
+	buildDNSRecordFilters(d, &input)
 
-	iter := conn.DNS.Records.ListAutoPaging(ctx, input)
-	for iter.Next() {
-		current := iter.Current()
-
+	err = listZoneDNSRecords(ctx, conn, input, func(current dns.RecordResponse) bool {
 		record := RecordInfo{
 			ZoneID:				zoneDetails.ID,
+			ZoneName:			zoneDetails.Name,
 			RecordResponse:		current,
 		}
 		d.StreamListItem(ctx, record)
 
 		// Context can be cancelled due to manual cancellation or the limit has been hit
-		if d.RowsRemaining(ctx) == 0 {
-			return nil, nil
-		}
-	}
-	if err := iter.Err(); err != nil {
+		return d.RowsRemaining(ctx) != 0
+	})
+	if err != nil {
 		logger.Error("cloudflare_dns_record.listDNSRecord", "DNSRecords api error", err)
 		return nil, err
 	}
@@ -112,6 +127,153 @@ func listDNSRecord(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 	return nil, nil
 }
 
+// listZoneDNSRecords pages through the records of a zone matching input,
+// calling record for each until it returns false.
+func listZoneDNSRecords(ctx context.Context, conn *cloudflare.Client, input dns.RecordListParams, record func(dns.RecordResponse) bool) error {
+	iter := conn.DNS.Records.ListAutoPaging(ctx, input)
+	for iter.Next() {
+		if !record(iter.Current()) {
+			return nil
+		}
+	}
+	return iter.Err()
+}
+
+// buildDNSRecordFilters narrows the listing with the API's filters. The
+// filters are case-insensitive and LIKE patterns may only be partly
+// expressible, so they can return extra records, which Postgres then drops.
+func buildDNSRecordFilters(d *plugin.QueryData, input *dns.RecordListParams) {
+	if recordType := d.EqualsQualString("type"); recordType != "" {
+		input.Type = cloudflare.F(dns.RecordListParamsType(recordType))
+	}
+	if d.EqualsQuals["proxied"] != nil {
+		input.Proxied = cloudflare.F(d.EqualsQuals["proxied"].GetBoolValue())
+	}
+
+	if filter, ok := dnsRecordTextFilterFromQuals(d.Quals["name"]); ok {
+		input.Name = cloudflare.F(filter)
+	}
+	// The content filter has the same fields as the name filter
+	if filter, ok := dnsRecordTextFilterFromQuals(d.Quals["content"]); ok {
+		input.Content = cloudflare.F(dns.RecordListParamsContent(filter))
+	}
+	if filter, ok := dnsRecordTextFilterFromQuals(d.Quals["comment"]); ok {
+		input.Comment = cloudflare.F(dns.RecordListParamsComment{
+			Exact:      filter.Exact,
+			Contains:   filter.Contains,
+			Startswith: filter.Startswith,
+			Endswith:   filter.Endswith,
+		})
+	}
+
+	// A tag is either a name that must be present, or name:value
+	if tag := d.EqualsQualString("tag"); tag != "" {
+		if strings.Contains(tag, ":") {
+			input.Tag = cloudflare.F(dns.RecordListParamsTag{Exact: cloudflare.F(tag)})
+		} else {
+			input.Tag = cloudflare.F(dns.RecordListParamsTag{Present: cloudflare.F(tag)})
+		}
+	}
+}
+
+// dnsRecordTextFilterFromQuals maps =, LIKE and ILIKE quals on a text column
+// onto the exact, contains, startswith and endswith filters the API has for
+// names, content and comments. When several quals need the same filter, the
+// first is used.
+func dnsRecordTextFilterFromQuals(quals *plugin.KeyColumnQuals) (dns.RecordListParamsName, bool) {
+	var filter dns.RecordListParamsName
+	if quals == nil {
+		return filter, false
+	}
+
+	found := false
+	for _, q := range quals.Quals {
+		value := q.Value.GetStringValue()
+		if value == "" {
+			continue
+		}
+
+		op, literal := "exact", value
+		if q.Operator != "=" {
+			op, literal = likePatternFilter(value)
+		}
+		switch {
+		case op == "exact" && !filter.Exact.Present:
+			filter.Exact = cloudflare.F(literal)
+		case op == "contains" && !filter.Contains.Present:
+			filter.Contains = cloudflare.F(literal)
+		case op == "startswith" && !filter.Startswith.Present:
+			filter.Startswith = cloudflare.F(literal)
+		case op == "endswith" && !filter.Endswith.Present:
+			filter.Endswith = cloudflare.F(literal)
+		default:
+			continue
+		}
+		found = true
+	}
+
+	return filter, found
+}
+
+// likePatternFilter works out the loosest API filter that every value
+// matching a LIKE pattern also matches: "exact", "startswith", "endswith" or
+// "contains", along with the literal text to filter on. Patterns without any
+// literal text return an empty op.
+func likePatternFilter(pattern string) (string, string) {
+	// Split the pattern into runs of literal text. A backslash makes the
+	// next character literal.
+	var parts []string
+	var literal strings.Builder
+	wildcards, leading, trailing, single := 0, false, false, false
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			literal.WriteRune(runes[i])
+		case r == '%' || r == '_':
+			if literal.Len() > 0 {
+				parts = append(parts, literal.String())
+				literal.Reset()
+			}
+			wildcards++
+			leading = leading || i == 0
+			trailing = i == len(runes)-1
+			single = single || r == '_'
+		default:
+			literal.WriteRune(r)
+		}
+	}
+	if literal.Len() > 0 {
+		parts = append(parts, literal.String())
+	}
+
+	switch {
+	case len(parts) == 0:
+		return "", ""
+	case wildcards == 0:
+		return "exact", parts[0]
+	case len(parts) == 1 && !single:
+		// A single literal run with % at either or both ends maps directly
+		if !leading {
+			return "startswith", parts[0]
+		}
+		if !trailing {
+			return "endswith", parts[0]
+		}
+		return "contains", parts[0]
+	}
+
+	// Otherwise filter on the longest run of literal text
+	longest := ""
+	for _, part := range parts {
+		if len(part) > len(longest) {
+			longest = part
+		}
+	}
+	return "contains", longest
+}
+
 func getDNSRecord(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
 	conn, err := connectV4(ctx, d)
@@ -140,3 +302,55 @@ func getDNSRecord(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 
 	return record, nil
 }
+
+// getDNSRecordZoneName returns the name of the record's zone. Listed records
+// already carry it from their parent zone; records fetched by ID need the
+// zone looked up.
+func getDNSRecordZoneName(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	record := h.Item.(RecordInfo)
+	if record.ZoneName != "" {
+		return record.ZoneName, nil
+	}
+
+	logger := plugin.Logger(ctx)
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_dns_record.getDNSRecordZoneName", "connection error", err)
+		return nil, err
+	}
+
+	zone, err := conn.Zones.Get(ctx, zones.ZoneGetParams{ZoneID: cloudflare.F(record.ZoneID)})
+	if err != nil {
+		logger.Error("cloudflare_dns_record.getDNSRecordZoneName", "Zone api error", err)
+		return nil, err
+	}
+
+	return zone.Name, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+// dnsRecordTagsToMap turns the record's "name:value" tags into a map. A tag
+// without a value maps to an empty string.
+func dnsRecordTagsToMap(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	var tags []string
+	switch v := d.Value.(type) {
+	case []dns.RecordTags:
+		tags = v
+	case []interface{}:
+		for _, tag := range v {
+			tags = append(tags, fmt.Sprint(tag))
+		}
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	tagMap := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		name, value, _ := strings.Cut(tag, ":")
+		tagMap[name] = value
+	}
+
+	return tagMap, nil
+}
@@ -0,0 +1,189 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/quals"
+)
+
+func TestDNSRecordList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_record")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "zone_id", "type", "name", "content", "ttl", "priority", "proxied", "proxiable", "locked", "created_on"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "372e67954025e0ba6aaa6d586b9e0b59", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "type": "A", "name": "example.com", "content": "198.51.100.4", "ttl": 1, "proxied": true, "created_on": "2024-01-01T05:20:00Z"},
+		{"id": "4a6d7b3c2e1f0a9b8c7d6e5f4a3b2c1d", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "type": "MX", "content": "mail.example.com", "ttl": 3600, "priority": 10, "proxied": false},
+		{"id": "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e", "zone_id": "9a7806061c88ada191ed06f989cc3dac", "type": "TXT", "name": "_dmarc.example.org", "content": "\"v=DMARC1; p=reject\""},
+	})
+}
+
+func TestDNSRecordListByZone(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_record")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "zone_id"}, eq("zone_id", "9a7806061c88ada191ed06f989cc3dac"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e", "zone_id": "9a7806061c88ada191ed06f989cc3dac"},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records"); n != 0 {
+		t.Errorf("records of a non-matching zone should not be listed, made %d calls", n)
+	}
+}
+
+func TestDNSRecordGet(t *testing.T) {
+	h := newReplayHarness(t, "", "dns_record")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "zone_id", "type", "content"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "372e67954025e0ba6aaa6d586b9e0b59"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "372e67954025e0ba6aaa6d586b9e0b59", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "type": "A", "content": "198.51.100.4"},
+	})
+}
+
+func TestDNSRecordListOwnership(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_record")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "zone_name", "comment", "comment_modified_on", "tags", "tags_src", "tags_modified_on", "settings"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":                  "372e67954025e0ba6aaa6d586b9e0b59",
+			"zone_name":           "example.com",
+			"comment":             "managed by terraform",
+			"comment_modified_on": "2024-02-02T05:20:00Z",
+			"tags":                map[string]any{"team": "web", "owner": "alice", "critical": ""},
+			"tags_src":            []any{"team:web", "owner:alice", "critical"},
+			"tags_modified_on":    "2024-02-01T05:20:00Z",
+			"settings":            map[string]any{"ipv4_only": true, "ipv6_only": false},
+		},
+		{"id": "4a6d7b3c2e1f0a9b8c7d6e5f4a3b2c1d", "zone_name": "example.com", "comment": nil, "comment_modified_on": nil, "tags": nil, "tags_modified_on": nil},
+		{"id": "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e", "zone_name": "example.org", "tags": nil},
+	})
+	// Listed records take the zone name from their parent zone
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353"); n != 0 {
+		t.Errorf("listed records should not look up their zone, made %d calls", n)
+	}
+}
+
+func TestDNSRecordGetZoneName(t *testing.T) {
+	h := newReplayHarness(t, "", "dns_record", "zone_details")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "zone_name", "tags"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "372e67954025e0ba6aaa6d586b9e0b59"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "372e67954025e0ba6aaa6d586b9e0b59", "zone_name": "example.com", "tags": map[string]any{"team": "web", "owner": "alice", "critical": ""}},
+	})
+}
+
+func TestDNSRecordGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "dns_record")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "ffffffffffffffffffffffffffffffff"))
+
+	assertRows(t, rows, "id", nil)
+}
+
+func TestDNSRecordListSkipsForbiddenZone(t *testing.T) {
+	// The token can read both zones but lacks DNS read on example.com
+	h := newReplayHarness(t, "", "zones", "dns_record")
+	h.server.handle(fixtureInteraction{
+		Path:   "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+		Status: 403,
+		Body:   []byte(`{"success":false,"errors":[{"code":9109,"message":"Unauthorized to access requested resource"}],"messages":[],"result":null}`),
+	})
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "zone_id"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e", "zone_id": "9a7806061c88ada191ed06f989cc3dac"},
+	})
+}
+
+func TestDNSRecordListFailsOnAuthenticationError(t *testing.T) {
+	// A revoked or invalid token fails the query instead of returning no rows
+	for _, status := range []int{401, 403} {
+		h := newReplayHarness(t, "", "zones", "dns_record")
+		h.server.handle(fixtureInteraction{
+			Path:   "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+			Status: status,
+			Body:   []byte(`{"success":false,"errors":[{"code":10000,"message":"Authentication error"}],"messages":[],"result":null}`),
+		})
+		_, err := h.query("cloudflare_dns_record", []string{"id"}, nil, 0)
+		if err == nil || !strings.Contains(err.Error(), "Authentication error") {
+			t.Errorf("status %d: got error %v, want the authentication error", status, err)
+		}
+	}
+}
+
+func TestDNSRecordListFilters(t *testing.T) {
+	// The type and name filters are sent to the API in every zone, so
+	// example.com returns no records
+	h := newReplayHarness(t, "", "zones", "dns_record")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "type", "name"},
+		eq("type", "TXT"), testQual{Column: "name", Operator: quals.QualOperatorLike, Value: "%_dmarc%"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e", "type": "TXT", "name": "_dmarc.example.org"},
+	})
+
+	for _, path := range []string{
+		"/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records",
+		"/client/v4/zones/9a7806061c88ada191ed06f989cc3dac/dns_records",
+	} {
+		r := h.server.lastRequest("GET", path)
+		if r == nil {
+			t.Fatalf("no request for %s", path)
+		}
+		query := r.URL.Query()
+		assertValue(t, "type filter", query.Get("type"), "TXT")
+		// _ is a LIKE wildcard, so only the literal text after it is sent
+		assertValue(t, "name filter", query.Get("name.contains"), "dmarc")
+	}
+}
+
+func TestDNSRecordListTextAndTagFilters(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_record")
+	h.mustQuery("cloudflare_dns_record", []string{"id"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"),
+		testQual{Column: "content", Operator: quals.QualOperatorILike, Value: "mail.%"},
+		eq("comment", "managed by terraform"),
+		eq("proxied", false),
+		eq("tag", "env:prod"))
+
+	r := h.server.lastRequest("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records")
+	if r == nil {
+		t.Fatal("no request for the zone's records")
+	}
+	query := r.URL.Query()
+	assertValue(t, "content filter", query.Get("content.startswith"), "mail.")
+	assertValue(t, "comment filter", query.Get("comment.exact"), "managed by terraform")
+	assertValue(t, "proxied filter", query.Get("proxied"), "false")
+	assertValue(t, "tag filter", query.Get("tag.exact"), "env:prod")
+
+	// A tag name alone only has to be present
+	h.mustQuery("cloudflare_dns_record", []string{"id"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("tag", "env"))
+	r = h.server.lastRequest("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records")
+	assertValue(t, "tag present filter", r.URL.Query().Get("tag.present"), "env")
+}
+
+func TestLikePatternFilter(t *testing.T) {
+	tests := []struct {
+		pattern, op, literal string
+	}{
+		{"example.com", "exact", "example.com"},
+		{"mail.%", "startswith", "mail."},
+		{"%.example.com", "endswith", ".example.com"},
+		{"%dmarc%", "contains", "dmarc"},
+		{"%_dmarc%", "contains", "dmarc"},
+		{"v=spf1%include:%", "contains", "include:"},
+		{`\_dmarc.%`, "startswith", "_dmarc."},
+		{`100\%`, "exact", "100%"},
+		{`a\%b%`, "startswith", "a%b"},
+		{"%", "", ""},
+	}
+	for _, tt := range tests {
+		op, literal := likePatternFilter(tt.pattern)
+		if op != tt.op || literal != tt.literal {
+			t.Errorf("likePatternFilter(%q) = %q, %q, want %q, %q", tt.pattern, op, literal, tt.op, tt.literal)
+		}
+	}
+}
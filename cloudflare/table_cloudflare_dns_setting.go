@@ -0,0 +1,83 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/query_cache"
+)
+
+func tableCloudflareDNSSetting(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_dns_setting",
+		Description: "DNS settings of a zone, such as multi-provider DNS, the NS record TTL and SOA record overrides.",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+			},
+			Hydrate:       listDNSSettings,
+			ParentHydrate: listZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID"), Description: "ID of the zone."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Description: "Name of the zone."},
+			{Name: "zone_mode", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneMode").NullIfZero(), Description: "Whether the zone is in standard, cdn_only or dns_only mode."},
+
+			// Other columns
+			{Name: "flatten_all_cnames", Type: proto.ColumnType_BOOL, Transform: transform.FromField("FlattenAllCNAMEs"), Description: "True if all CNAME records in the zone are flattened, not only the one at the apex."},
+			{Name: "foundation_dns", Type: proto.ColumnType_BOOL, Transform: transform.FromField("FoundationDNS"), Description: "True if the zone uses Foundation DNS advanced nameservers."},
+			{Name: "multi_provider", Type: proto.ColumnType_BOOL, Description: "True if the zone is served by other DNS providers as well, so NS records at the apex are kept and the zone can be activated without Cloudflare nameservers."},
+			{Name: "ns_ttl", Type: proto.ColumnType_INT, Transform: transform.FromField("NSTTL").NullIfZero(), Description: "The TTL of the zone's NS records, in seconds."},
+			{Name: "secondary_overrides", Type: proto.ColumnType_BOOL, Description: "True if the proxied records and settings of a secondary zone override the records transferred from its primary."},
+			{Name: "nameservers_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("Nameservers.Type").NullIfZero(), Description: "The kind of nameservers assigned to the zone, e.g. cloudflare.standard or custom.account."},
+			{Name: "nameservers_ns_set", Type: proto.ColumnType_INT, Transform: transform.FromField("Nameservers.NSSet").NullIfZero(), Description: "The custom nameserver set assigned to the zone, for custom nameserver types."},
+			{Name: "internal_dns_reference_zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("InternalDNS.ReferenceZoneID").NullIfZero(), Description: "The ID of the zone internal DNS queries fall back to, for internal zones."},
+			{Name: "soa", Type: proto.ColumnType_JSON, Transform: transform.FromField("SOA"), Description: "The values of the zone's SOA record: mname, rname, refresh, retry, expire, min_ttl and ttl."},
+		}),
+	}
+}
+
+type DNSSettingInfo struct {
+	ZoneID   string
+	ZoneName string
+	dns.SettingZoneGetResponse
+}
+
+//// LIST FUNCTION
+
+func listDNSSettings(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	zone := h.Item.(zones.Zone)
+
+	// Only list settings for zones stated in the input query
+	inputZoneId := d.EqualsQualString("zone_id")
+	if inputZoneId != "" && inputZoneId != zone.ID {
+		return nil, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_dns_setting.listDNSSettings", "connection error", err)
+		return nil, err
+	}
+
+	setting, err := conn.DNS.Settings.Zone.Get(ctx, dns.SettingZoneGetParams{ZoneID: cloudflare.F(zone.ID)})
+	if err != nil {
+		logger.Error("cloudflare_dns_setting.listDNSSettings", "api error", err)
+		return nil, err
+	}
+
+	d.StreamListItem(ctx, DNSSettingInfo{
+		ZoneID:                 zone.ID,
+		ZoneName:               zone.Name,
+		SettingZoneGetResponse: *setting,
+	})
+
+	return nil, nil
+}
@@ -0,0 +1,60 @@
+package cloudflare
+
+import "testing"
+
+func TestDNSSettingList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_setting")
+	rows := h.mustQuery("cloudflare_dns_setting", []string{
+		"zone_id", "zone_name", "zone_mode", "flatten_all_cnames", "foundation_dns", "multi_provider", "ns_ttl",
+		"secondary_overrides", "nameservers_type", "nameservers_ns_set", "internal_dns_reference_zone_id",
+	})
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id":             "023e105f4ecef8ad9ca31a8372d0c353",
+			"zone_name":           "example.com",
+			"zone_mode":           "standard",
+			"flatten_all_cnames":  false,
+			"foundation_dns":      true,
+			"multi_provider":      true,
+			"ns_ttl":              86400,
+			"secondary_overrides": false,
+			"nameservers_type":    "cloudflare.standard",
+		},
+		{
+			"zone_id":             "9a7806061c88ada191ed06f989cc3dac",
+			"zone_name":           "example.org",
+			"zone_mode":           "dns_only",
+			"flatten_all_cnames":  true,
+			"foundation_dns":      false,
+			"multi_provider":      false,
+			"ns_ttl":              3600,
+			"secondary_overrides": false,
+			"nameservers_type":    "custom.account",
+			"nameservers_ns_set":  2,
+		},
+	})
+}
+
+func TestDNSSettingListByZone(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_setting")
+	rows := h.mustQuery("cloudflare_dns_setting", []string{"zone_id", "soa"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id": "023e105f4ecef8ad9ca31a8372d0c353",
+			"soa": map[string]any{
+				"expire":  604800,
+				"min_ttl": 1800,
+				"mname":   "kristina.ns.cloudflare.com",
+				"refresh": 10000,
+				"retry":   2400,
+				"rname":   "admin.example.com",
+				"ttl":     3600,
+			},
+		},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/zones/9a7806061c88ada191ed06f989cc3dac/dns_settings"); n != 0 {
+		t.Errorf("expected no request for the other zone's settings, got %d", n)
+	}
+}
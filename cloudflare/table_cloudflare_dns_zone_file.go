@@ -0,0 +1,96 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/query_cache"
+)
+
+func tableCloudflareDNSZoneFile(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_dns_zone_file",
+		Description: "The DNS records of a zone exported as a BIND zone file.",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+			},
+			Hydrate:       listDNSZoneFile,
+			ParentHydrate: listZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID"), Description: "ID of the zone."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Description: "Name of the zone."},
+			{Name: "content", Type: proto.ColumnType_STRING, Description: "The zone file exactly as exported by Cloudflare."},
+
+			// JSON columns
+			{Name: "records", Type: proto.ColumnType_JSON, Description: "The resource records in the zone file, each with its owner, ttl, class, type, rdata fields and any comment, such as the cf_tags Cloudflare adds for proxied records."},
+
+			// Other columns
+			{Name: "record_count", Type: proto.ColumnType_INT, Description: "The number of resource records in the zone file."},
+			{Name: "parse_error", Type: proto.ColumnType_STRING, Transform: transform.FromField("ParseError").NullIfZero(), Description: "Why the zone file could not be parsed, in which case records and record_count are null."},
+		}),
+	}
+}
+
+type DNSZoneFileInfo struct {
+	ZoneID      string
+	ZoneName    string
+	Content     string
+	Records     []zoneFileRecord
+	RecordCount *int
+	ParseError  string
+}
+
+func listDNSZoneFile(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	zone := h.Item.(zones.Zone)
+
+	// Only export zones stated in the input query
+	inputZoneId := d.EqualsQualString("zone_id")
+	if inputZoneId != "" && inputZoneId != zone.ID {
+		return nil, nil
+	}
+
+	logger := plugin.Logger(ctx)
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_dns_zone_file.listDNSZoneFile", "connection error", err)
+		return nil, err
+	}
+
+	content, err := conn.DNS.Records.Export(ctx, dns.RecordExportParams{ZoneID: cloudflare.F(zone.ID)})
+	if err != nil {
+		logger.Error("cloudflare_dns_zone_file.listDNSZoneFile", "DNSRecords export api error", err)
+		return nil, err
+	}
+
+	zoneFile := DNSZoneFileInfo{
+		ZoneID:   zone.ID,
+		ZoneName: zone.Name,
+	}
+	if content != nil {
+		zoneFile.Content = *content
+	}
+
+	// A zone file that cannot be parsed is still returned as exported, so
+	// one zone does not fail the query for the others
+	records, err := parseZoneFile(zoneFile.Content, zone.Name)
+	if err != nil {
+		logger.Warn("cloudflare_dns_zone_file.listDNSZoneFile", "zone", zone.ID, "zone file parse error", err)
+		zoneFile.ParseError = err.Error()
+	} else {
+		recordCount := len(records)
+		zoneFile.Records = records
+		zoneFile.RecordCount = &recordCount
+	}
+
+	d.StreamListItem(ctx, zoneFile)
+
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDNSZoneFileList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_zone_file")
+	rows := h.mustQuery("cloudflare_dns_zone_file", []string{"zone_id", "zone_name", "content", "record_count", "records"})
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{"zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "zone_name": "example.com", "record_count": 5},
+		{
+			"zone_id":      "9a7806061c88ada191ed06f989cc3dac",
+			"zone_name":    "example.org",
+			"record_count": 3,
+			"records": []any{
+				map[string]any{"owner": "example.org", "ttl": 3600, "class": "IN", "type": "SOA", "rdata": []any{"bob.ns.cloudflare.com.", "dns.cloudflare.com.", "2046424121", "10000", "2400", "604800", "3600"}, "comment": "serial"},
+				map[string]any{"owner": "_dmarc.example.org", "ttl": 300, "class": "IN", "type": "TXT", "rdata": []any{`"v=DMARC1; p=reject"`}},
+				map[string]any{"owner": "www.example.org", "ttl": 3600, "class": "IN", "type": "CNAME", "rdata": []any{"example.org."}},
+			},
+		},
+	})
+
+	sortRows(rows, "zone_id")
+	if content, _ := rows[0]["content"].(string); !strings.HasPrefix(content, ";;\n;; Domain:     example.com.") {
+		t.Errorf("content should be the exported zone file, got %q", content)
+	}
+}
+
+func TestDNSZoneFileListByZone(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "dns_zone_file")
+	rows := h.mustQuery("cloudflare_dns_zone_file", []string{"zone_id", "records"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	// The export writes the SOA owner without a trailing dot, which makes it
+	// relative to the zone
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id": "023e105f4ecef8ad9ca31a8372d0c353",
+			"records": []any{
+				map[string]any{"owner": "example.com.example.com", "ttl": 3600, "class": "IN", "type": "SOA", "rdata": []any{"bob.ns.cloudflare.com.", "dns.cloudflare.com.", "2046424120", "10000", "2400", "604800", "3600"}},
+				map[string]any{"owner": "example.com", "ttl": 86400, "class": "IN", "type": "NS", "rdata": []any{"bob.ns.cloudflare.com."}},
+				map[string]any{"owner": "example.com", "ttl": 86400, "class": "IN", "type": "NS", "rdata": []any{"lola.ns.cloudflare.com."}},
+				map[string]any{"owner": "example.com", "ttl": 1, "class": "IN", "type": "A", "rdata": []any{"198.51.100.4"}, "comment": "cf_tags=cf-proxied:true"},
+				map[string]any{"owner": "example.com", "ttl": 3600, "class": "IN", "type": "MX", "rdata": []any{"10", "mail.example.com."}},
+			},
+		},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/zones/9a7806061c88ada191ed06f989cc3dac/dns_records/export"); n != 0 {
+		t.Errorf("a non-matching zone should not be exported, made %d calls", n)
+	}
+}
+
+func TestDNSZoneFileListParseError(t *testing.T) {
+	// A zone file that cannot be parsed is returned as exported, and the
+	// other zones are still listed
+	h := newReplayHarness(t, "", "zones", "dns_zone_file")
+	h.server.handle(fixtureInteraction{
+		Path:    "/client/v4/zones/9a7806061c88ada191ed06f989cc3dac/dns_records/export",
+		Headers: map[string]string{"Content-Type": "text/plain"},
+		Body:    []byte(`"$INCLUDE other.zone\n"`),
+	})
+	rows := h.mustQuery("cloudflare_dns_zone_file", []string{"zone_id", "content", "records", "record_count", "parse_error"})
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{"zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "record_count": 5, "parse_error": nil},
+		{"zone_id": "9a7806061c88ada191ed06f989cc3dac", "content": "$INCLUDE other.zone\n", "records": nil, "record_count": nil},
+	})
+	if msg, _ := rows[1]["parse_error"].(string); !strings.Contains(msg, "$INCLUDE") {
+		t.Errorf("parse_error = %q, want the unsupported directive named", msg)
+	}
+}
@@ -0,0 +1,14 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFirewallRuleDeprecated(t *testing.T) {
+	h := newReplayHarness(t, "")
+	_, err := h.query("cloudflare_firewall_rule", []string{"id"}, nil, 0)
+	if err == nil || !strings.Contains(err.Error(), "use cloudflare_ruleset table instead") {
+		t.Fatalf("got error %v, want the deprecation error", err)
+	}
+}
@@ -29,8 +29,10 @@ func tableCloudflareHealthcheck(ctx context.Context) *plugin.Table {
 				{Name: "id", Require: plugin.Required},
 				{Name: "zone_id", Require: plugin.Required},
 			},
-			ShouldIgnoreError: isNotFoundError([]string{"Invalid healthcheck identifier"}),
-			Hydrate:           getHealthcheck,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getHealthcheck,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
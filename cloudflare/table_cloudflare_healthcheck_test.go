@@ -0,0 +1,30 @@
+package cloudflare
+
+import "testing"
+
+func TestHealthcheckList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "healthcheck")
+	rows := h.mustQuery("cloudflare_healthcheck", []string{"id", "zone_id", "name", "address", "type", "status", "interval", "retries", "check_regions", "http_config"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":            "023e105f4ecef8ad9ca31a8372d0c353",
+			"zone_id":       "023e105f4ecef8ad9ca31a8372d0c353",
+			"name":          "server-1",
+			"address":       "www.example.com",
+			"type":          "HTTPS",
+			"status":        "healthy",
+			"interval":      60,
+			"retries":       2,
+			"check_regions": []any{"WEU", "ENAM"},
+		},
+	})
+	assertValue(t, "http_config.path", rows[0]["http_config"].(map[string]any)["path"], "/health")
+}
+
+func TestHealthcheckGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "healthcheck")
+	rows := h.mustQuery("cloudflare_healthcheck", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "ffffffffffffffffffffffffffffffff"))
+
+	assertRows(t, rows, "id", nil)
+}
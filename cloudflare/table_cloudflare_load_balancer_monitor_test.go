@@ -0,0 +1,23 @@
+package cloudflare
+
+import "testing"
+
+func TestLoadBalancerMonitorList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "load_balancer_monitor")
+	rows := h.mustQuery("cloudflare_load_balancer_monitor", []string{"id", "type", "method", "path", "header", "timeout", "interval", "expected_codes", "follow_redirects", "probe_zone"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":               "f1aba936b94213e5b8dca0c0dbf1f9cc",
+			"type":             "https",
+			"method":           "GET",
+			"path":             "/health",
+			"header":           map[string]any{"Host": []any{"example.com"}},
+			"timeout":          3,
+			"interval":         90,
+			"expected_codes":   "2xx",
+			"follow_redirects": true,
+			"probe_zone":       "example.com",
+		},
+	})
+}
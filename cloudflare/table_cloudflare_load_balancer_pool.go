@@ -2,7 +2,6 @@ package cloudflare
 
 import (
 	"context"
-	"strings"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/accounts"
@@ -96,8 +95,8 @@ func getLoadBalancerPoolHealth(ctx context.Context, d *plugin.QueryData, h *plug
 
 	pool_health, err := conn.LoadBalancers.Pools.Health.Get(ctx, pool.ID, input)
 	if err != nil {
-		// This setting might not be available for all zones
-		if strings.Contains(err.Error(), "Health info unavailable") {
+		// Health info is unavailable for pools that have not been checked yet
+		if isNotFoundError(err) {
 			return nil, nil
 		}
 		logger.Error("cloudflare_load_balancer_pool.getLoadBalancerPoolHealth", "load balancer pool health API error", err)
@@ -0,0 +1,25 @@
+package cloudflare
+
+import "testing"
+
+func TestLoadBalancerPoolList(t *testing.T) {
+	// Health for the disabled pool is unavailable, which leaves the column
+	// empty instead of failing the row
+	h := newReplayHarness(t, "", "accounts", "load_balancer_pool")
+	rows := h.mustQuery("cloudflare_load_balancer_pool", []string{"id", "name", "enabled", "monitor", "minimum_origins", "account_name", "check_regions", "origins", "health"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":              "17b5962d775c646f3f9725cbc7a53df4",
+			"name":            "primary-dc-1",
+			"enabled":         true,
+			"monitor":         "f1aba936b94213e5b8dca0c0dbf1f9cc",
+			"minimum_origins": 2,
+			"account_name":    "Example Account",
+			"check_regions":   []any{"WEU", "ENAM"},
+		},
+		{"id": "9290f38c5d07c2e2f4df57b1f61d4196", "name": "secondary-dc-1", "enabled": false, "health": nil},
+	})
+	health := rows[0]["health"].(map[string]any)
+	assertValue(t, "health.pool_id", health["pool_id"], "17b5962d775c646f3f9725cbc7a53df4")
+}
@@ -0,0 +1,22 @@
+package cloudflare
+
+import "testing"
+
+func TestLoadBalancerList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "load_balancer")
+	rows := h.mustQuery("cloudflare_load_balancer", []string{"id", "name", "zone_id", "zone_name", "enabled", "proxied", "fallback_pool", "steering_policy", "default_pools"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":              "699d98642c564d2e855e9661899b7252",
+			"name":            "lb.example.com",
+			"zone_id":         "023e105f4ecef8ad9ca31a8372d0c353",
+			"zone_name":       "example.com",
+			"enabled":         true,
+			"proxied":         true,
+			"fallback_pool":   "17b5962d775c646f3f9725cbc7a53df4",
+			"steering_policy": "dynamic_latency",
+			"default_pools":   []any{"17b5962d775c646f3f9725cbc7a53df4", "9290f38c5d07c2e2f4df57b1f61d4196"},
+		},
+	})
+}
@@ -18,10 +18,11 @@ func tableCloudflareLogpushJob(ctx context.Context) *plugin.Table {
 		Description: "Cloudflare Logpush job is a configuration that automatically ships log data from a specific zone or account to a chosen external destination in near real‑time batch delivery",
 		List: &plugin.ListConfig{
 			KeyColumns: []*plugin.KeyColumn{
-				{Name: "account_id", Require: plugin.AnyOf},
-				{Name: "zone_id", Require: plugin.AnyOf},
+				{Name: "account_id", Require: plugin.Optional},
+				{Name: "zone_id", Require: plugin.Optional},
 			},
-			Hydrate: listLogpushJobs,
+			Hydrate:       listLogpushJobs,
+			ParentHydrate: listAccountsAndZones,
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: []*plugin.KeyColumn{
@@ -29,8 +30,10 @@ func tableCloudflareLogpushJob(ctx context.Context) *plugin.Table {
 				{Name: "account_id", Require: plugin.AnyOf},
 				{Name: "zone_id", Require: plugin.AnyOf},
 			},
-			ShouldIgnoreError: isNotFoundError([]string{"Invalid logpush job identifier"}),
-			Hydrate:           getLogpushJob,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getLogpushJob,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -50,8 +53,8 @@ func tableCloudflareLogpushJob(ctx context.Context) *plugin.Table {
 			{Name: "name", Type: proto.ColumnType_STRING, Description: "Optional human readable job name."},
 
 			// Query columns for filtering
-			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromQual("account_id"), Description: "The account ID to filter logpush jobs."},
-			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromQual("zone_id"), Description: "The zone ID to filter logpush jobs."},
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID").NullIfZero(), Description: "ID of the account an account-level logpush job belongs to."},
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID").NullIfZero(), Description: "ID of the zone a zone-level logpush job belongs to."},
 		
 			// JSON Columns
 			{Name: "output_options", Type: proto.ColumnType_JSON, Description: "The structured replacement for `logpull_options`."},
@@ -59,14 +62,21 @@ func tableCloudflareLogpushJob(ctx context.Context) *plugin.Table {
 	}
 }
 
+type LogpushJobInfo struct {
+	AccountID string
+	ZoneID    string
+	logpush.LogpushJob
+}
+
 //// LIST FUNCTION
 
-// listLogpushJobs retrieves all logpush jobs for the specified account_id or zone_id.
+// listLogpushJobs retrieves all logpush jobs of the account or zone streamed
+// by listAccountsAndZones.
 //
 // This function handles both account-level and zone-level logpush jobs:
 // - Account-level logpush jobs (account_id)
 // - Zone-level logpush jobs (zone_id)
-func listLogpushJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+func listLogpushJobs(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
 	conn, err := connectV4(ctx, d)
 	if err != nil {
@@ -74,17 +84,7 @@ func listLogpushJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 		return nil, err
 	}
 
-	// Get the qualifiers
-	quals := d.EqualsQuals
-	accountID := ""
-	zoneID := ""
-
-	if quals["account_id"] != nil {
-		accountID = quals["account_id"].GetStringValue()
-	}
-	if quals["zone_id"] != nil {
-		zoneID = quals["zone_id"].GetStringValue()
-	}
+	accountID, zoneID := accountOrZoneScope(d, h)
 
 	// Build API parameters based on account or zone context
 	input := logpush.JobListParams{}
@@ -98,8 +98,11 @@ func listLogpushJobs(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydrate
 	// Execute paginated API call
 	iter := conn.Logpush.Jobs.ListAutoPaging(ctx, input)
 	for iter.Next() {
-		job := iter.Current()
-		d.StreamListItem(ctx, job)
+		d.StreamListItem(ctx, LogpushJobInfo{
+			AccountID:  accountID,
+			ZoneID:     zoneID,
+			LogpushJob: iter.Current(),
+		})
 
 		// Context can be cancelled due to manual cancellation or the limit has been hit
 		if d.RowsRemaining(ctx) == 0 {
@@ -133,10 +136,15 @@ func getLogpushJob(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 		return nil, err
 	}
 
-	quals := d.EqualsQuals
-	logpushJobID := quals["id"].GetInt64Value()
-	accountID := quals["account_id"].GetStringValue()
-	zoneID := quals["zone_id"].GetStringValue()
+	logpushJobID := d.EqualsQuals["id"].GetInt64Value()
+	accountID, zoneID := accountOrZoneScope(d, nil)
+
+	// Jobs of accounts and zones outside the connection's scope are not
+	// returned, as in the list
+	ok, err := accountOrZoneInScope(ctx, d, accountID, zoneID)
+	if err != nil || !ok {
+		return nil, err
+	}
 
 	// Build API parameters with appropriate context
 	input := logpush.JobGetParams{}
@@ -154,5 +162,9 @@ func getLogpushJob(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 		return nil, err
 	}
 
-	return job, nil
+	return LogpushJobInfo{
+		AccountID:  accountID,
+		ZoneID:     zoneID,
+		LogpushJob: *job,
+	}, nil
 }
@@ -0,0 +1,76 @@
+package cloudflare
+
+import "testing"
+
+func TestLogpushJobListByAccount(t *testing.T) {
+	h := newReplayHarness(t, "", "logpush_job")
+	rows := h.mustQuery("cloudflare_logpush_job", []string{"id", "account_id", "zone_id", "dataset", "destination_conf", "enabled", "max_upload_bytes", "last_complete", "output_options"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":               1,
+			"account_id":       "01a7362d577a6c3019a474fd6f485823",
+			"zone_id":          nil,
+			"dataset":          "audit_logs",
+			"destination_conf": "s3://mybucket/logs?region=us-west-2",
+			"enabled":          true,
+			"max_upload_bytes": 5000000,
+			"last_complete":    "2024-01-01T05:20:00Z",
+		},
+	})
+}
+
+func TestLogpushJobListByZone(t *testing.T) {
+	h := newReplayHarness(t, "", "logpush_job")
+	rows := h.mustQuery("cloudflare_logpush_job", []string{"id", "zone_id", "dataset", "frequency", "logpull_options"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": 2, "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "dataset": "http_requests", "frequency": "high", "logpull_options": "fields=RayID,ClientIP&timestamps=rfc3339"},
+	})
+}
+
+func TestLogpushJobGet(t *testing.T) {
+	h := newReplayHarness(t, "", "logpush_job")
+	rows := h.mustQuery("cloudflare_logpush_job", []string{"id", "name"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", 2))
+
+	assertRows(t, rows, "id", []map[string]any{{"id": 2, "name": "http logs"}})
+
+	rows = h.mustQuery("cloudflare_logpush_job", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", 99))
+	assertRows(t, rows, "id", nil)
+}
+
+func TestLogpushJobGetScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `exclude_zones = ["example.com"]`, "zone_details", "logpush_job")
+	rows := h.mustQuery("cloudflare_logpush_job", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", 2))
+	assertRows(t, rows, "id", nil)
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/logpush/jobs/2"); n != 0 {
+		t.Errorf("fetched a job of an excluded zone %d times, want 0", n)
+	}
+
+	h = newReplayHarness(t, `zones = ["example.com"]`, "zone_details", "logpush_job")
+	rows = h.mustQuery("cloudflare_logpush_job", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", 2))
+	assertRows(t, rows, "id", []map[string]any{{"id": 2}})
+}
+
+func TestLogpushJobListAllScopes(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "zones", "logpush_job")
+	rows := h.mustQuery("cloudflare_logpush_job", []string{"id", "account_id", "zone_id", "dataset"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": 1, "account_id": "01a7362d577a6c3019a474fd6f485823", "zone_id": nil, "dataset": "audit_logs"},
+		{"id": 2, "account_id": nil, "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "dataset": "http_requests"},
+	})
+}
+
+func TestLogpushJobListByZoneSkipsWalk(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "zones", "logpush_job")
+	h.mustQuery("cloudflare_logpush_job", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	if n := h.server.requestCount("GET", "/client/v4/zones"); n != 0 {
+		t.Errorf("listed zones %d times, want 0", n)
+	}
+	if n := h.server.requestCount("GET", "/client/v4/accounts"); n != 0 {
+		t.Errorf("listed accounts %d times, want 0", n)
+	}
+}
@@ -0,0 +1,13 @@
+package cloudflare
+
+import "testing"
+
+func TestManagedTransformList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "managed_transform")
+	rows := h.mustQuery("cloudflare_managed_transform", []string{"id", "type", "enabled", "has_conflict", "zone_id", "conflicts_with"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "add_bot_protection_headers", "type": "request_header", "enabled": true, "has_conflict": false, "zone_id": "023e105f4ecef8ad9ca31a8372d0c353"},
+		{"id": "remove_x-powered-by_header", "type": "response_header", "enabled": false, "has_conflict": true, "conflicts_with": []any{"add_security_headers"}},
+	})
+}
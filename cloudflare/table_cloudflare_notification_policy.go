@@ -29,8 +29,10 @@ func tableCloudflareNotificationPolicy(ctx context.Context) *plugin.Table {
 				{Name: "id", Require: plugin.Required},
 				{Name: "account_id", Require: plugin.Required},
 			},
-			ShouldIgnoreError: isNotFoundError([]string{"Invalid notification policy identifier"}),
-			Hydrate:           getNotificationPolicy,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getNotificationPolicy,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -0,0 +1,28 @@
+package cloudflare
+
+import "testing"
+
+func TestNotificationPolicyList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "notification_policy")
+	rows := h.mustQuery("cloudflare_notification_policy", []string{"id", "name", "account_id", "alert_type", "alert_interval", "enabled", "created", "mechanisms", "filters"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":             "0da2b59e-f118-439d-8097-bdfb215203c9",
+			"name":           "SSL Notification Event Policy",
+			"account_id":     "01a7362d577a6c3019a474fd6f485823",
+			"alert_type":     "universal_ssl_event_type",
+			"alert_interval": "30m",
+			"enabled":        true,
+			"created":        "2024-01-01T05:20:00Z",
+		},
+	})
+}
+
+func TestNotificationPolicyGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "notification_policy")
+	rows := h.mustQuery("cloudflare_notification_policy", []string{"id"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("id", "ffffffff-ffff-ffff-ffff-ffffffffffff"))
+
+	assertRows(t, rows, "id", nil)
+}
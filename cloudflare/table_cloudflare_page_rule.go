@@ -35,9 +35,11 @@ func tableCloudflarePageRule(ctx context.Context) *plugin.Table {
 			ParentHydrate: listZones,
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns:        plugin.AllColumns([]string{"zone_id", "id"}),
-			ShouldIgnoreError: isNotFoundError([]string{"HTTP status 404"}),
-			Hydrate:           getPageRule,
+			KeyColumns: plugin.AllColumns([]string{"zone_id", "id"}),
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getPageRule,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
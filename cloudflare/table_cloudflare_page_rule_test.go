@@ -0,0 +1,27 @@
+package cloudflare
+
+import "testing"
+
+func TestPageRuleList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "page_rule")
+	rows := h.mustQuery("cloudflare_page_rule", []string{"id", "zone_id", "status", "priority", "actions", "targets"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":       "023e105f4ecef8ad9ca31a8372d0c353",
+			"zone_id":  "023e105f4ecef8ad9ca31a8372d0c353",
+			"status":   "active",
+			"priority": 1,
+			"actions":  []any{map[string]any{"id": "browser_check", "value": "on"}},
+			"targets":  []any{map[string]any{"target": "url", "constraint": map[string]any{"operator": "matches", "value": "*example.com/images/*"}}},
+		},
+	})
+}
+
+func TestPageRuleGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "page_rule")
+	rows := h.mustQuery("cloudflare_page_rule", []string{"id"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "ffffffffffffffffffffffffffffffff"))
+
+	assertRows(t, rows, "id", nil)
+}
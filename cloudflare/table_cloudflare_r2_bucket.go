@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
-	"github.com/aws/smithy-go"
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/option"
+	"github.com/cloudflare/cloudflare-go/v4/r2"
 	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
@@ -22,12 +25,27 @@ func tableCloudflareR2Bucket(ctx context.Context) *plugin.Table {
 		Description:      "Cloudflare R2 Buckets",
 		DefaultTransform: transform.FromCamel().NullIfZero(),
 		List: &plugin.ListConfig{
-			Hydrate:    listR2Buckets,
-			KeyColumns: plugin.SingleColumn("account_id"),
+			Hydrate: listR2Buckets,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "account_id", Require: plugin.Required},
+				{Name: "jurisdiction", Require: plugin.Optional},
+			},
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns: plugin.AllColumns([]string{"name", "account_id"}),
-			Hydrate:    getR2Bucket,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "name", Require: plugin.Required},
+				{Name: "account_id", Require: plugin.Required},
+				{Name: "jurisdiction", Require: plugin.Optional},
+			},
+			Hydrate: getR2Bucket,
+		},
+		// Buckets are listed through the S3 API when there are S3 keys and
+		// through the v4 API otherwise, so only the S3-only hydrates skip the
+		// v4 API limiters
+		HydrateConfig: []plugin.HydrateConfig{
+			{Func: getBucketEncryption, Tags: s3APITags()},
+			{Func: getBucketCORS, Tags: s3APITags()},
+			{Func: getBucketLocation, Tags: s3APITags()},
 		},
 		Columns: commonColumns([]*plugin.Column{
 			{
@@ -40,6 +58,60 @@ func tableCloudflareR2Bucket(ctx context.Context) *plugin.Table {
 				Description: "The date and time when bucket was created.",
 				Type:        proto.ColumnType_TIMESTAMP,
 			},
+			{
+				Name:        "jurisdiction",
+				Description: "The jurisdiction the bucket's data is stored in, one of default, eu or fedramp.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "storage_class",
+				Description: "The default storage class for newly uploaded objects, one of Standard or InfrequentAccess.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getR2BucketDetails,
+				Transform:   transform.FromField("StorageClass"),
+			},
+			{
+				Name:        "managed_domain",
+				Description: "The bucket's r2.dev domain.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getR2BucketManagedDomain,
+				Transform:   transform.FromField("Domain"),
+			},
+			{
+				Name:        "managed_domain_enabled",
+				Description: "True if the bucket is publicly accessible at its r2.dev domain.",
+				Type:        proto.ColumnType_BOOL,
+				Hydrate:     getR2BucketManagedDomain,
+				Transform:   transform.FromField("Enabled"),
+			},
+			{
+				Name:        "custom_domains",
+				Description: "The custom domains the bucket is connected to, with their status and minimum TLS version.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getR2BucketCustomDomains,
+				Transform:   transform.FromField("Domains"),
+			},
+			{
+				Name:        "lifecycle_rules",
+				Description: "The object lifecycle rules of the bucket, which delete objects, abort multipart uploads or change storage class after a set age or date.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getR2BucketLifecycle,
+				Transform:   transform.FromField("Rules"),
+			},
+			{
+				Name:        "event_notifications",
+				Description: "The queues that receive event notifications for the bucket, and the rules that trigger them.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getR2BucketEventNotifications,
+				Transform:   transform.FromField("Queues"),
+			},
+			{
+				Name:        "sippy",
+				Description: "The Sippy incremental migration configuration of the bucket, including its source bucket.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getR2BucketSippy,
+				Transform:   transform.FromValue(),
+			},
 			{
 				Name:        "server_side_encryption_configuration",
 				Description: "The default encryption configuration for the bucket.",
@@ -81,7 +153,17 @@ func tableCloudflareR2Bucket(ctx context.Context) *plugin.Table {
 
 type BucketData = struct {
 	types.Bucket
-	AccountId string
+	AccountId    string
+	Jurisdiction string
+	// Set when the bucket was read through the Cloudflare API rather than S3
+	Details *r2.Bucket
+}
+
+// r2BucketListEnvelope is the R2 bucket list response, including the cursor
+// for the next page that the client does not return
+type r2BucketListEnvelope struct {
+	Result     r2.BucketListResponse                   `json:"result"`
+	ResultInfo r2.BucketListResponseEnvelopeResultInfo `json:"result_info"`
 }
 
 //// LIST FUNCTION
@@ -98,38 +180,121 @@ func listR2Buckets(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 	// Can we do something with this error?
 	// Error: operation error S3: ListBuckets, exceeded maximum number of attempts, 3, https response error StatusCode: 0, RequestID: , HostID: , request send failed, Get "https://<account>.r2.cloudflarestorage.com/": remote error: tls: handshake failure (SQLSTATE HV000)
 
-	// Get R2 client
-	conn, err := getR2Client(ctx, d, accountID)
+	jurisdictions, err := getR2Jurisdictions(d, accountID)
+	if errors.Is(err, errR2CredentialsNotFound) {
+		// Without S3 keys, list the buckets with the connection's API token
+		return listR2BucketsFromAPI(ctx, d, accountID)
+	}
 	if err != nil {
-		logger.Error("cloudflare_r2_bucket.listR2Buckets", "R2 client error", err)
+		logger.Error("cloudflare_r2_bucket.listR2Buckets", "R2 credentials error", err)
 		return nil, err
 	}
 
-	// execute list call
-	input := &s3.ListBucketsInput{}
-	bucketsResult, err := conn.ListBuckets(ctx, input)
-	if err != nil {
-		// Get "https://<account>.r2.cloudflarestorage.com/": remote error: tls: handshake failure (SQLSTATE HV000)
-		if strings.Contains(err.Error(), "tls: handshake failure") {
-			return nil, nil
+	// Each jurisdiction has its own endpoint, which only lists its own buckets
+	for _, jurisdiction := range jurisdictions {
+		// Get R2 client
+		conn, err := getR2Client(ctx, d, accountID, jurisdiction)
+		if err != nil {
+			logger.Error("cloudflare_r2_bucket.listR2Buckets", "R2 client error", err)
+			return nil, err
 		}
 
-		logger.Error("cloudflare_r2_bucket.listR2Buckets", "api_error", err)
+		// execute list call
+		input := &s3.ListBucketsInput{}
+		bucketsResult, err := conn.ListBuckets(ctx, input)
+		if err != nil {
+			// Get "https://<account>.r2.cloudflarestorage.com/": remote error: tls: handshake failure (SQLSTATE HV000)
+			if strings.Contains(err.Error(), "tls: handshake failure") {
+				continue
+			}
+			if isR2JurisdictionUnavailable(d, jurisdiction, err) {
+				logger.Debug("cloudflare_r2_bucket.listR2Buckets", "jurisdiction", jurisdiction, "skipping unavailable jurisdiction", err)
+				continue
+			}
+
+			logger.Error("cloudflare_r2_bucket.listR2Buckets", "api_error", err)
+			return nil, err
+		}
+
+		for _, bucket := range bucketsResult.Buckets {
+			d.StreamListItem(ctx, BucketData{Bucket: bucket, AccountId: accountID, Jurisdiction: jurisdiction})
+
+			// Context may get cancelled due to manual cancellation or if the limit has been reached
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// listR2BucketsFromAPI lists buckets through the Cloudflare API, one
+// jurisdiction at a time
+func listR2BucketsFromAPI(ctx context.Context, d *plugin.QueryData, accountID string) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.listR2BucketsFromAPI", "connection_error", err)
+		return nil, err
+	}
+
+	jurisdictions, err := getR2APIJurisdictions(d)
+	if err != nil {
 		return nil, err
 	}
+	for _, jurisdiction := range jurisdictions {
+		input := r2.BucketListParams{
+			AccountID:    cloudflare.F(accountID),
+			PerPage:      cloudflare.F(float64(1000)),
+			Jurisdiction: cloudflare.F(r2.BucketListParamsCfR2Jurisdiction(jurisdiction)),
+		}
+		for {
+			var page r2BucketListEnvelope
+			_, err := conn.R2.Buckets.List(ctx, input, option.WithResponseBodyInto(&page))
+			if err != nil {
+				if isR2JurisdictionUnavailable(d, jurisdiction, err) {
+					logger.Debug("cloudflare_r2_bucket.listR2BucketsFromAPI", "jurisdiction", jurisdiction, "skipping unavailable jurisdiction", err)
+					break
+				}
+				logger.Error("cloudflare_r2_bucket.listR2BucketsFromAPI", "api_error", err)
+				return nil, err
+			}
 
-	for _, bucket := range bucketsResult.Buckets {
-		d.StreamListItem(ctx, BucketData{bucket, accountID})
+			for _, bucket := range page.Result.Buckets {
+				d.StreamListItem(ctx, newR2BucketData(bucket, accountID, jurisdiction))
 
-		// Context may get cancelled due to manual cancellation or if the limit has been reached
-		if d.RowsRemaining(ctx) == 0 {
-			return nil, nil
+				// Context may get cancelled due to manual cancellation or if the limit has been reached
+				if d.RowsRemaining(ctx) == 0 {
+					return nil, nil
+				}
+			}
+
+			if page.ResultInfo.Cursor == "" || len(page.Result.Buckets) == 0 {
+				break
+			}
+			input.Cursor = cloudflare.F(page.ResultInfo.Cursor)
 		}
 	}
 
 	return nil, nil
 }
 
+// newR2BucketData converts a bucket from the Cloudflare API into the shape
+// listed through S3
+func newR2BucketData(bucket r2.Bucket, accountID string, jurisdiction string) BucketData {
+	data := BucketData{
+		Bucket:       types.Bucket{Name: aws.String(bucket.Name)},
+		AccountId:    accountID,
+		Jurisdiction: jurisdiction,
+		Details:      &bucket,
+	}
+	if created, err := time.Parse(time.RFC3339, bucket.CreationDate); err == nil {
+		data.CreationDate = &created
+	}
+	return data
+}
+
 //// HYDRATE FUNCTIONS
 
 // do not have a get call for R2 bucket.
@@ -144,25 +309,73 @@ func getR2Bucket(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData
 		return nil, nil
 	}
 
-	// Get R2 client
-	conn, err := getR2Client(ctx, d, accountID)
+	jurisdictions, err := getR2Jurisdictions(d, accountID)
+	if errors.Is(err, errR2CredentialsNotFound) {
+		return getR2BucketFromAPI(ctx, d, accountID, bucketName)
+	}
 	if err != nil {
-		logger.Error("cloudflare_r2_bucket.getR2Bucket", "R2 client error", err)
+		logger.Error("cloudflare_r2_bucket.getR2Bucket", "R2 credentials error", err)
 		return nil, err
 	}
 
-	// execute list call
-	input := &s3.ListBucketsInput{}
-	listBucketsOutput, err := conn.ListBuckets(ctx, input)
+	for _, jurisdiction := range jurisdictions {
+		// Get R2 client
+		conn, err := getR2Client(ctx, d, accountID, jurisdiction)
+		if err != nil {
+			logger.Error("cloudflare_r2_bucket.getR2Bucket", "R2 client error", err)
+			return nil, err
+		}
+
+		// execute list call
+		input := &s3.ListBucketsInput{}
+		listBucketsOutput, err := conn.ListBuckets(ctx, input)
+		if err != nil {
+			if isR2JurisdictionUnavailable(d, jurisdiction, err) {
+				logger.Debug("cloudflare_r2_bucket.getR2Bucket", "jurisdiction", jurisdiction, "skipping unavailable jurisdiction", err)
+				continue
+			}
+			logger.Error("cloudflare_r2_bucket.getR2Bucket", "api_error", err)
+			return nil, err
+		}
+
+		for _, bucket := range listBucketsOutput.Buckets {
+			if aws.ToString(bucket.Name) == bucketName {
+				return BucketData{Bucket: bucket, AccountId: accountID, Jurisdiction: jurisdiction}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getR2BucketFromAPI looks a bucket up through the Cloudflare API in each
+// jurisdiction in turn
+func getR2BucketFromAPI(ctx context.Context, d *plugin.QueryData, accountID string, bucketName string) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	conn, err := connectV4(ctx, d)
 	if err != nil {
-		logger.Error("cloudflare_r2_bucket.getR2Bucket", "api_error", err)
+		logger.Error("cloudflare_r2_bucket.getR2BucketFromAPI", "connection_error", err)
 		return nil, err
 	}
 
-	for _, bucket := range listBucketsOutput.Buckets {
-		if bucket.Name == aws.String(bucketName) {
-			return BucketData{bucket, accountID}, nil
+	jurisdictions, err := getR2APIJurisdictions(d)
+	if err != nil {
+		return nil, err
+	}
+	for _, jurisdiction := range jurisdictions {
+		input := r2.BucketGetParams{
+			AccountID:    cloudflare.F(accountID),
+			Jurisdiction: cloudflare.F(r2.BucketGetParamsCfR2Jurisdiction(jurisdiction)),
+		}
+		bucket, err := conn.R2.Buckets.Get(ctx, bucketName, input)
+		if err != nil {
+			if isNotFoundError(err) || isR2JurisdictionUnavailable(d, jurisdiction, err) {
+				continue
+			}
+			logger.Error("cloudflare_r2_bucket.getR2BucketFromAPI", "api_error", err)
+			return nil, err
 		}
+		return newR2BucketData(*bucket, accountID, jurisdiction), nil
 	}
 
 	return nil, nil
@@ -174,8 +387,12 @@ func getBucketLocation(ctx context.Context, d *plugin.QueryData, h *plugin.Hydra
 	bucketData := h.Item.(BucketData)
 
 	// Get R2 client
-	conn, err := getR2Client(ctx, d, bucketData.AccountId)
+	conn, err := getR2Client(ctx, d, bucketData.AccountId, bucketData.Jurisdiction)
 	if err != nil {
+		// Only readable through S3, so left empty when no S3 keys are configured
+		if errors.Is(err, errR2CredentialsNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -204,8 +421,12 @@ func getBucketEncryption(ctx context.Context, d *plugin.QueryData, h *plugin.Hyd
 	bucketData := h.Item.(BucketData)
 
 	// Get R2 client
-	conn, err := getR2Client(ctx, d, bucketData.AccountId)
+	conn, err := getR2Client(ctx, d, bucketData.AccountId, bucketData.Jurisdiction)
 	if err != nil {
+		// Only readable through S3, so left empty when no S3 keys are configured
+		if errors.Is(err, errR2CredentialsNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -215,11 +436,8 @@ func getBucketEncryption(ctx context.Context, d *plugin.QueryData, h *plugin.Hyd
 	}
 	encryption, err := conn.GetBucketEncryption(ctx, input)
 	if err != nil {
-		var a smithy.APIError
-		if errors.As(err, &a) {
-			if a.ErrorCode() == "ServerSideEncryptionConfigurationNotFoundError" {
-				return nil, nil
-			}
+		if hasS3ErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -232,8 +450,12 @@ func getBucketCORS(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 	bucketData := h.Item.(BucketData)
 
 	// Get R2 client
-	conn, err := getR2Client(ctx, d, bucketData.AccountId)
+	conn, err := getR2Client(ctx, d, bucketData.AccountId, bucketData.Jurisdiction)
 	if err != nil {
+		// Only readable through S3, so left empty when no S3 keys are configured
+		if errors.Is(err, errR2CredentialsNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
@@ -243,14 +465,158 @@ func getBucketCORS(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 	}
 	cors, err := conn.GetBucketCors(ctx, input)
 	if err != nil {
-		var a smithy.APIError
-		if errors.As(err, &a) {
-			if a.ErrorCode() == "NoSuchCORSConfiguration" {
-				return nil, nil
-			}
+		if hasS3ErrorCode(err, "NoSuchCORSConfiguration") {
+			return nil, nil
 		}
 		return nil, err
 	}
 
 	return cors, nil
 }
+
+func getR2BucketDetails(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	bucketData := h.Item.(BucketData)
+
+	// Buckets listed through the Cloudflare API already have their details
+	if bucketData.Details != nil {
+		return bucketData.Details, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketDetails", "connection_error", err)
+		return nil, err
+	}
+
+	input := r2.BucketGetParams{
+		AccountID:    cloudflare.F(bucketData.AccountId),
+		Jurisdiction: cloudflare.F(r2.BucketGetParamsCfR2Jurisdiction(bucketData.Jurisdiction)),
+	}
+	bucket, err := conn.R2.Buckets.Get(ctx, aws.ToString(bucketData.Name), input)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketDetails", "api_error", err)
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+func getR2BucketManagedDomain(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	bucketData := h.Item.(BucketData)
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketManagedDomain", "connection_error", err)
+		return nil, err
+	}
+
+	input := r2.BucketDomainManagedListParams{
+		AccountID:    cloudflare.F(bucketData.AccountId),
+		Jurisdiction: cloudflare.F(r2.BucketDomainManagedListParamsCfR2Jurisdiction(bucketData.Jurisdiction)),
+	}
+	domain, err := conn.R2.Buckets.Domains.Managed.List(ctx, aws.ToString(bucketData.Name), input)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketManagedDomain", "api_error", err)
+		return nil, err
+	}
+
+	return domain, nil
+}
+
+func getR2BucketCustomDomains(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	bucketData := h.Item.(BucketData)
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketCustomDomains", "connection_error", err)
+		return nil, err
+	}
+
+	input := r2.BucketDomainCustomListParams{
+		AccountID:    cloudflare.F(bucketData.AccountId),
+		Jurisdiction: cloudflare.F(r2.BucketDomainCustomListParamsCfR2Jurisdiction(bucketData.Jurisdiction)),
+	}
+	domains, err := conn.R2.Buckets.Domains.Custom.List(ctx, aws.ToString(bucketData.Name), input)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketCustomDomains", "api_error", err)
+		return nil, err
+	}
+
+	return domains, nil
+}
+
+func getR2BucketLifecycle(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	bucketData := h.Item.(BucketData)
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketLifecycle", "connection_error", err)
+		return nil, err
+	}
+
+	input := r2.BucketLifecycleGetParams{
+		AccountID:    cloudflare.F(bucketData.AccountId),
+		Jurisdiction: cloudflare.F(r2.BucketLifecycleGetParamsCfR2Jurisdiction(bucketData.Jurisdiction)),
+	}
+	lifecycle, err := conn.R2.Buckets.Lifecycle.Get(ctx, aws.ToString(bucketData.Name), input)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketLifecycle", "api_error", err)
+		return nil, err
+	}
+
+	return lifecycle, nil
+}
+
+func getR2BucketEventNotifications(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	bucketData := h.Item.(BucketData)
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketEventNotifications", "connection_error", err)
+		return nil, err
+	}
+
+	input := r2.BucketEventNotificationGetParams{
+		AccountID:    cloudflare.F(bucketData.AccountId),
+		Jurisdiction: cloudflare.F(r2.BucketEventNotificationGetParamsCfR2Jurisdiction(bucketData.Jurisdiction)),
+	}
+	notifications, err := conn.R2.Buckets.EventNotifications.Get(ctx, aws.ToString(bucketData.Name), input)
+	if err != nil {
+		// Buckets without event notifications have no configuration to get
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		logger.Error("cloudflare_r2_bucket.getR2BucketEventNotifications", "api_error", err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func getR2BucketSippy(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	bucketData := h.Item.(BucketData)
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketSippy", "connection_error", err)
+		return nil, err
+	}
+
+	input := r2.BucketSippyGetParams{
+		AccountID:    cloudflare.F(bucketData.AccountId),
+		Jurisdiction: cloudflare.F(r2.BucketSippyGetParamsCfR2Jurisdiction(bucketData.Jurisdiction)),
+	}
+	sippy, err := conn.R2.Buckets.Sippy.Get(ctx, aws.ToString(bucketData.Name), input)
+	if err != nil {
+		logger.Error("cloudflare_r2_bucket.getR2BucketSippy", "api_error", err)
+		return nil, err
+	}
+
+	return sippy, nil
+}
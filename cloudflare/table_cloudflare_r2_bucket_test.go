@@ -0,0 +1,221 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestR2BucketList(t *testing.T) {
+	// logs has neither an encryption nor a CORS configuration; both columns
+	// are left empty rather than failing the row
+	h := newReplayHarness(t, "", "r2_bucket")
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "account_id", "creation_date", "region", "server_side_encryption_configuration", "cors", "title"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "assets", "account_id": "01a7362d577a6c3019a474fd6f485823", "creation_date": "2024-01-01T05:20:00Z", "region": "WNAM", "title": "assets"},
+		{"name": "logs", "creation_date": "2024-02-01T05:20:00Z", "region": "us-east-1", "server_side_encryption_configuration": nil, "cors": nil},
+	})
+
+	encryption := rows[0]["server_side_encryption_configuration"].(map[string]any)
+	rule := encryption["Rules"].([]any)[0].(map[string]any)
+	assertValue(t, "encryption algorithm", rule["ApplyServerSideEncryptionByDefault"].(map[string]any)["SSEAlgorithm"], "AES256")
+
+	cors := rows[0]["cors"].(map[string]any)
+	assertValue(t, "cors allowed origins", cors["CORSRules"].([]any)[0].(map[string]any)["AllowedOrigins"], []any{"https://example.com"})
+
+	if r := h.server.lastRequest("GET", "/r2/01a7362d577a6c3019a474fd6f485823"); r == nil || r.Header.Get("Authorization") == "" {
+		t.Errorf("expected a signed ListBuckets request against the account endpoint")
+	}
+}
+
+func TestR2BucketListJurisdictions(t *testing.T) {
+	h := newReplayHarness(t, r2JurisdictionConfig, "r2_jurisdiction")
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "assets", "jurisdiction": "default"},
+		{"name": "eu-archive", "jurisdiction": "eu"},
+	})
+	r := h.server.lastRequest("GET", "/r2/eu/01a7362d577a6c3019a474fd6f485823")
+	if r == nil || !strings.Contains(r.Header.Get("Authorization"), "Credential=eu-access-key/") {
+		t.Errorf("expected the EU endpoint to be signed with the EU credentials")
+	}
+
+	// A second account of the same user connection uses its own credentials
+	rows = h.mustQuery("cloudflare_r2_bucket", []string{"name", "jurisdiction"},
+		eq("account_id", "2b0e4c8f1d3a5e7c9b2d4f6a8c0e2a4b"))
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "reports", "jurisdiction": "default"},
+	})
+	r = h.server.lastRequest("GET", "/r2/default/2b0e4c8f1d3a5e7c9b2d4f6a8c0e2a4b")
+	if r == nil || !strings.Contains(r.Header.Get("Authorization"), "Credential=second-access-key/") {
+		t.Errorf("expected the second account to be signed with its own credentials")
+	}
+}
+
+func TestR2BucketListJurisdictionQual(t *testing.T) {
+	h := newReplayHarness(t, r2JurisdictionConfig, "r2_jurisdiction")
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("jurisdiction", "eu"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "eu-archive", "jurisdiction": "eu"},
+	})
+	if n := h.server.requestCount("GET", "/r2/default/01a7362d577a6c3019a474fd6f485823"); n != 0 {
+		t.Errorf("listed the default jurisdiction %d times, want 0", n)
+	}
+}
+
+func TestR2BucketListSettings(t *testing.T) {
+	// Settings come from the Cloudflare API for buckets listed through S3; logs
+	// has no event notification configuration, which is left empty
+	h := newReplayHarness(t, "", "r2_bucket")
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "storage_class", "managed_domain", "managed_domain_enabled", "custom_domains", "lifecycle_rules", "event_notifications", "sippy"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "assets", "storage_class": "Standard", "managed_domain": "pub-0113a9e4549cf9b1f3c5a5b9c4f5e1d2.r2.dev", "managed_domain_enabled": true},
+		{"name": "logs", "storage_class": "InfrequentAccess", "managed_domain_enabled": false, "custom_domains": []any{}, "event_notifications": nil},
+	})
+
+	domain := rows[0]["custom_domains"].([]any)[0].(map[string]any)
+	assertValue(t, "custom domain", domain["domain"], "assets.example.com")
+	assertValue(t, "custom domain min TLS", domain["minTLS"], "1.2")
+
+	rule := rows[1]["lifecycle_rules"].([]any)[0].(map[string]any)
+	assertValue(t, "lifecycle rule id", rule["id"], "expire-old-logs")
+	condition := rule["deleteObjectsTransition"].(map[string]any)["condition"].(map[string]any)
+	assertValue(t, "lifecycle max age", condition["maxAge"], float64(2592000))
+
+	queue := rows[0]["event_notifications"].([]any)[0].(map[string]any)
+	assertValue(t, "event notification queue", queue["queueName"], "asset-uploads")
+	assertValue(t, "event notification actions", queue["rules"].([]any)[0].(map[string]any)["actions"], []any{"PutObject", "CompleteMultipartUpload"})
+
+	sippy := rows[1]["sippy"].(map[string]any)
+	assertValue(t, "sippy enabled", sippy["enabled"], true)
+	assertValue(t, "sippy source", sippy["source"].(map[string]any)["bucket"], "legacy-logs")
+
+	r := h.server.lastRequest("GET", "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/r2/buckets/assets/lifecycle")
+	if r == nil || r.Header.Get("cf-r2-jurisdiction") != "default" {
+		t.Errorf("expected the lifecycle request to name the bucket's jurisdiction")
+	}
+}
+
+// r2NoKeysConfig leaves out the S3 API keys, so only the API token is set
+const r2NoKeysConfig = `
+access_key = ""
+secret_key = ""
+`
+
+func TestR2BucketListWithoutS3Keys(t *testing.T) {
+	// Buckets are listed through the Cloudflare API in every jurisdiction,
+	// following the cursor, and S3-only columns are left empty
+	h := newReplayHarness(t, r2NoKeysConfig, "r2_bucket")
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "creation_date", "jurisdiction", "storage_class", "region", "cors"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "assets", "creation_date": "2024-01-01T05:20:00Z", "jurisdiction": "default", "storage_class": "Standard", "region": nil, "cors": nil},
+		{"name": "eu-archive", "creation_date": "2024-03-01T05:20:00Z", "jurisdiction": "eu", "storage_class": "Standard"},
+		{"name": "logs", "creation_date": "2024-02-01T05:20:00Z", "jurisdiction": "default", "storage_class": "InfrequentAccess"},
+	})
+	if n := h.server.requestCount("GET", "/r2/01a7362d577a6c3019a474fd6f485823"); n != 0 {
+		t.Errorf("made %d S3 requests without S3 keys, want 0", n)
+	}
+	if n := h.server.requestCount("GET", "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/r2/buckets/assets"); n != 0 {
+		t.Errorf("fetched listed bucket details %d times, want 0", n)
+	}
+}
+
+func TestR2BucketGetWithoutS3Keys(t *testing.T) {
+	h := newReplayHarness(t, r2NoKeysConfig, "r2_bucket")
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "jurisdiction", "storage_class"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("name", "assets"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "assets", "jurisdiction": "default", "storage_class": "Standard"},
+	})
+}
+
+func TestR2BucketGet(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_bucket")
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "creation_date", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("name", "logs"))
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "logs", "creation_date": "2024-02-01T05:20:00Z", "jurisdiction": "default"},
+	})
+}
+
+func TestR2BucketListSkipsUnavailableJurisdictions(t *testing.T) {
+	// Most accounts cannot use the fedramp jurisdiction, which is skipped
+	// when listing every jurisdiction but not when asked for by name
+	forbidden := fixtureInteraction{
+		Path:           "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/r2/buckets",
+		RequestHeaders: map[string]string{"cf-r2-jurisdiction": "fedramp"},
+		Status:         403,
+		Body:           []byte(`{"success":false,"errors":[{"code":10000,"message":"Authentication error"}],"messages":[],"result":null}`),
+	}
+
+	h := newReplayHarness(t, r2NoKeysConfig, "r2_bucket")
+	h.server.handle(forbidden)
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+	assertRows(t, rows, "name", []map[string]any{
+		{"name": "assets", "jurisdiction": "default"},
+		{"name": "eu-archive", "jurisdiction": "eu"},
+		{"name": "logs", "jurisdiction": "default"},
+	})
+
+	h = newReplayHarness(t, r2NoKeysConfig, "r2_bucket")
+	h.server.handle(forbidden)
+	_, err := h.query("cloudflare_r2_bucket", []string{"name"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("jurisdiction", "fedramp")}, 0)
+	if err == nil {
+		t.Errorf("expected an error listing the fedramp jurisdiction by name")
+	}
+}
+
+func TestR2BucketGetSkipsUnavailableJurisdictions(t *testing.T) {
+	// The EU keys are configured but the account cannot use the EU
+	// jurisdiction, so a get over the S3 API skips it as the list does
+	forbidden := fixtureInteraction{
+		Path:   "/r2/eu/01a7362d577a6c3019a474fd6f485823",
+		Status: 403,
+		Body:   []byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`),
+	}
+
+	h := newReplayHarness(t, r2JurisdictionConfig, "r2_jurisdiction")
+	h.server.handle(forbidden)
+	rows := h.mustQuery("cloudflare_r2_bucket", []string{"name", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("name", "missing"))
+	assertRows(t, rows, "name", nil)
+	if n := h.server.requestCount("GET", "/r2/eu/01a7362d577a6c3019a474fd6f485823"); n != 1 {
+		t.Errorf("listed the EU buckets %d times, want 1", n)
+	}
+
+	rows = h.mustQuery("cloudflare_r2_bucket", []string{"name", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+	assertRows(t, rows, "name", []map[string]any{{"name": "assets", "jurisdiction": "default"}})
+
+	h = newReplayHarness(t, r2JurisdictionConfig, "r2_jurisdiction")
+	h.server.handle(forbidden)
+	_, err := h.query("cloudflare_r2_bucket", []string{"name"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("name", "missing"), eq("jurisdiction", "eu")}, 0)
+	if err == nil {
+		t.Errorf("expected an error getting a bucket from the EU jurisdiction by name")
+	}
+}
+
+func TestR2BucketListInvalidJurisdiction(t *testing.T) {
+	for _, config := range []string{"", r2NoKeysConfig} {
+		h := newReplayHarness(t, config, "r2_bucket")
+		_, err := h.query("cloudflare_r2_bucket", []string{"name"},
+			[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("jurisdiction", "us")}, 0)
+		if err == nil || !strings.Contains(err.Error(), `invalid jurisdiction "us"`) {
+			t.Errorf("got error %v, want the jurisdiction to be rejected", err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestR2BucketUsageList(t *testing.T) {
+	// Without a depth the whole bucket is totalled, across every page
+	h := newReplayHarness(t, "", "r2_bucket_usage")
+	rows := h.mustQuery("cloudflare_r2_bucket_usage", []string{"key_prefix", "depth", "object_count", "size", "oldest_last_modified", "newest_last_modified", "bucket", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "usage"))
+
+	assertRows(t, rows, "key_prefix", []map[string]any{
+		{"key_prefix": "", "depth": int64(0), "object_count": int64(6), "size": int64(13650), "oldest_last_modified": "2023-12-01T00:00:00Z", "newest_last_modified": "2024-03-01T00:00:00Z", "bucket": "usage", "jurisdiction": "default"},
+	})
+}
+
+func TestR2BucketUsageListDepth(t *testing.T) {
+	// Objects at the top of the bucket are grouped under the empty prefix
+	h := newReplayHarness(t, "", "r2_bucket_usage")
+	rows := h.mustQuery("cloudflare_r2_bucket_usage", []string{"key_prefix", "depth", "object_count", "size", "oldest_last_modified", "newest_last_modified"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "usage"), eq("depth", int64(1)))
+
+	assertRows(t, rows, "key_prefix", []map[string]any{
+		{"key_prefix": "", "depth": int64(1), "object_count": int64(1), "size": int64(100)},
+		{"key_prefix": "logs/", "object_count": int64(4), "size": int64(3550), "oldest_last_modified": "2024-01-10T00:00:00Z", "newest_last_modified": "2024-03-01T00:00:00Z"},
+		{"key_prefix": "media/", "object_count": int64(1), "size": int64(10000)},
+	})
+}
+
+func TestR2BucketUsageListPrefixDepth(t *testing.T) {
+	// Depth counts levels below the prefix
+	h := newReplayHarness(t, "", "r2_bucket_usage")
+	rows := h.mustQuery("cloudflare_r2_bucket_usage", []string{"key_prefix", "prefix", "object_count", "size"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "usage"), eq("prefix", "logs/"), eq("depth", int64(2)))
+
+	assertRows(t, rows, "key_prefix", []map[string]any{
+		{"key_prefix": "logs/", "prefix": "logs/", "object_count": int64(1), "size": int64(50)},
+		{"key_prefix": "logs/2024/01/", "object_count": int64(2), "size": int64(3000)},
+		{"key_prefix": "logs/2024/02/", "object_count": int64(1), "size": int64(500)},
+	})
+}
+
+func TestR2BucketUsageListEmpty(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_bucket_usage")
+	rows := h.mustQuery("cloudflare_r2_bucket_usage", []string{"key_prefix", "object_count", "size", "oldest_last_modified"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "empty"), eq("depth", int64(1)))
+
+	assertRows(t, rows, "key_prefix", []map[string]any{
+		{"key_prefix": "", "object_count": int64(0), "size": int64(0), "oldest_last_modified": nil},
+	})
+}
+
+func TestR2BucketUsageListNegativeDepth(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_bucket_usage")
+	_, err := h.query("cloudflare_r2_bucket_usage", []string{"key_prefix"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "usage"), eq("depth", int64(-1))}, 0)
+	if err == nil || !strings.Contains(err.Error(), "depth must be 0 or more") {
+		t.Fatalf("got error %v, want a depth error", err)
+	}
+}
+
+func TestR2KeyPrefix(t *testing.T) {
+	tests := []struct {
+		key, prefix string
+		depth       int
+		want        string
+	}{
+		{"logs/2024/01/a.log", "", 0, ""},
+		{"logs/2024/01/a.log", "", 1, "logs/"},
+		{"logs/2024/01/a.log", "", 3, "logs/2024/01/"},
+		{"logs/2024/01/a.log", "", 5, "logs/2024/01/"},
+		{"README.md", "", 2, ""},
+		{"logs/2024/01/a.log", "logs/20", 1, "logs/2024/"},
+		{"logs/", "", 1, "logs/"},
+	}
+	for _, tt := range tests {
+		if got := r2KeyPrefix(tt.key, tt.prefix, tt.depth); got != tt.want {
+			t.Errorf("r2KeyPrefix(%q, %q, %d) = %q, want %q", tt.key, tt.prefix, tt.depth, got, tt.want)
+		}
+	}
+}
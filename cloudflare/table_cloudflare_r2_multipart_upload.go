@@ -0,0 +1,259 @@
+package cloudflare
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableCloudflareR2MultipartUpload(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:             "cloudflare_r2_multipart_upload",
+		Description:      "Multipart uploads to a Cloudflare R2 bucket that have been started but not completed or aborted.",
+		DefaultTransform: transform.FromCamel().NullIfZero(),
+		Tags:             s3APITags(),
+		List: &plugin.ListConfig{
+			Hydrate: listR2MultipartUploads,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "account_id", Require: plugin.Required},
+				{Name: "bucket", Require: plugin.Required},
+				{Name: "key", Require: plugin.Optional},
+				{Name: "prefix", Require: plugin.Optional},
+				{Name: "jurisdiction", Require: plugin.Optional},
+			},
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+		},
+		Columns: commonColumns([]*plugin.Column{
+			{
+				Name:        "upload_id",
+				Description: "The ID of the multipart upload.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Upload.UploadId"),
+			},
+			{
+				Name:        "key",
+				Description: "The key of the object being uploaded.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Upload.Key"),
+			},
+			{
+				Name:        "initiated",
+				Description: "The date and time the multipart upload was started.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Upload.Initiated"),
+			},
+			{
+				Name:        "storage_class",
+				Description: "The storage class the object will be stored in.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Upload.StorageClass"),
+			},
+			{
+				Name:        "part_count",
+				Description: "The number of parts uploaded so far.",
+				Type:        proto.ColumnType_INT,
+				Hydrate:     getR2MultipartUploadParts,
+				Transform:   transform.FromField("PartCount"),
+			},
+			{
+				Name:        "size",
+				Description: "The total size in bytes of the parts uploaded so far, which are billed as stored data until the upload is completed or aborted.",
+				Type:        proto.ColumnType_INT,
+				Hydrate:     getR2MultipartUploadParts,
+				Transform:   transform.FromField("Size"),
+			},
+			{
+				Name:        "last_part_modified",
+				Description: "The date and time the most recent part was uploaded.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Hydrate:     getR2MultipartUploadParts,
+				Transform:   transform.FromField("LastModified"),
+			},
+			{
+				Name:        "initiator",
+				Description: "The identity that started the multipart upload.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Upload.Initiator"),
+			},
+			{
+				Name:        "owner",
+				Description: "The owner of the object being uploaded.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Upload.Owner"),
+			},
+
+			// steampipe standard columns
+			{
+				Name:        "title",
+				Description: "Title of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Upload.Key"),
+			},
+			{
+				Name:        "account_id",
+				Description: "ID of the account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("account_id"),
+			},
+			{
+				Name:        "bucket",
+				Description: "The name of the bucket the object is being uploaded to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("bucket"),
+			},
+			{
+				Name:        "prefix",
+				Description: "The prefix of the key of the object being uploaded.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("prefix"),
+			},
+			{
+				Name:        "jurisdiction",
+				Description: "The jurisdiction of the bucket, one of default, eu or fedramp.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Jurisdiction"),
+			},
+		}),
+	}
+}
+
+type r2MultipartUpload struct {
+	Upload       types.MultipartUpload
+	AccountID    string
+	Bucket       string
+	Jurisdiction string
+}
+
+type r2MultipartUploadParts struct {
+	PartCount    int
+	Size         int64
+	LastModified *time.Time
+}
+
+//// LIST FUNCTION
+
+func listR2MultipartUploads(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	accountID := d.EqualsQualString("account_id")
+	bucketName := d.EqualsQualString("bucket")
+
+	// get R2 client
+	conn, jurisdiction, err := getR2BucketClient(ctx, d, accountID, bucketName)
+	if err != nil {
+		logger.Error("cloudflare_r2_multipart_upload.listR2MultipartUploads", "R2 client error", err)
+		return nil, err
+	}
+
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucketName),
+	}
+
+	// A key is listed as a prefix, then matched exactly below
+	key := d.EqualsQualString("key")
+	if prefix := d.EqualsQualString("prefix"); prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	if key != "" {
+		input.Prefix = aws.String(key)
+	}
+
+	// Reduce the page size if a limit is set. R2 returns at most 1000
+	// uploads per page regardless.
+	maxUploads := int32(1000)
+	if d.QueryContext.Limit != nil {
+		// Compare before narrowing, so a limit beyond the int32 range
+		// cannot wrap around
+		limit := *d.QueryContext.Limit
+		if limit < int64(maxUploads) {
+			maxUploads = int32(limit)
+		}
+	}
+	input.MaxUploads = maxUploads
+
+	// There is no paginator for ListMultipartUploads, whose pages are marked
+	// by both a key and an upload ID
+	for {
+		output, err := conn.ListMultipartUploads(ctx, input)
+		if err != nil {
+			logger.Error("cloudflare_r2_multipart_upload.listR2MultipartUploads", "api_error", err)
+			return nil, err
+		}
+
+		for _, upload := range output.Uploads {
+			if key != "" && aws.ToString(upload.Key) != key {
+				continue
+			}
+			d.StreamListItem(ctx, r2MultipartUpload{
+				Upload:       upload,
+				AccountID:    accountID,
+				Bucket:       bucketName,
+				Jurisdiction: jurisdiction,
+			})
+
+			// Context may get cancelled due to manual cancellation or if the limit has been reached
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+
+		if !output.IsTruncated {
+			break
+		}
+		input.KeyMarker = output.NextKeyMarker
+		input.UploadIdMarker = output.NextUploadIdMarker
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+// getR2MultipartUploadParts totals the parts uploaded so far
+func getR2MultipartUploadParts(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	upload := h.Item.(r2MultipartUpload)
+
+	conn, err := getR2Client(ctx, d, upload.AccountID, upload.Jurisdiction)
+	if err != nil {
+		logger.Error("cloudflare_r2_multipart_upload.getR2MultipartUploadParts", "R2 client error", err)
+		return nil, err
+	}
+
+	parts := &r2MultipartUploadParts{}
+	paginator := s3.NewListPartsPaginator(conn, &s3.ListPartsInput{
+		Bucket:   aws.String(upload.Bucket),
+		Key:      upload.Upload.Key,
+		UploadId: upload.Upload.UploadId,
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			// The upload may have been completed or aborted since it was listed
+			if hasS3ErrorCode(err, "NoSuchUpload") {
+				return nil, nil
+			}
+			logger.Error("cloudflare_r2_multipart_upload.getR2MultipartUploadParts", "api_error", err)
+			return nil, err
+		}
+
+		for _, part := range output.Parts {
+			parts.PartCount++
+			parts.Size += part.Size
+			if part.LastModified != nil && (parts.LastModified == nil || part.LastModified.After(*parts.LastModified)) {
+				parts.LastModified = part.LastModified
+			}
+		}
+	}
+
+	return parts, nil
+}
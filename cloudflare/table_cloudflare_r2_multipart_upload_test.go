@@ -0,0 +1,41 @@
+package cloudflare
+
+import "testing"
+
+func TestR2MultipartUploadList(t *testing.T) {
+	// Uploads are paged by key and upload ID markers, and parts by part
+	// number. upload-3 was completed after it was listed, so its parts are
+	// left empty.
+	h := newReplayHarness(t, "", "r2_multipart_upload")
+	rows := h.mustQuery("cloudflare_r2_multipart_upload", []string{"upload_id", "key", "initiated", "storage_class", "part_count", "size", "last_part_modified", "account_id", "bucket", "jurisdiction"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "uploads-bucket"))
+
+	assertRows(t, rows, "upload_id", []map[string]any{
+		{"upload_id": "upload-1", "key": "backups/db.tar", "initiated": "2024-03-01T10:00:00Z", "storage_class": "STANDARD", "part_count": int64(3), "size": int64(11534336), "last_part_modified": "2024-03-01T10:10:00Z", "account_id": "01a7362d577a6c3019a474fd6f485823", "bucket": "uploads-bucket", "jurisdiction": "default"},
+		{"upload_id": "upload-2", "key": "backups/db.tar", "part_count": int64(0), "size": int64(0), "last_part_modified": nil},
+		{"upload_id": "upload-3", "key": "backups/db.tar.old", "part_count": nil, "size": nil},
+		{"upload_id": "upload-4", "key": "videos/intro.mp4", "part_count": int64(1), "size": int64(10485760)},
+	})
+}
+
+func TestR2MultipartUploadListPrefix(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_multipart_upload")
+	rows := h.mustQuery("cloudflare_r2_multipart_upload", []string{"upload_id", "key", "prefix"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "uploads-bucket"), eq("prefix", "videos/"))
+
+	assertRows(t, rows, "upload_id", []map[string]any{
+		{"upload_id": "upload-4", "key": "videos/intro.mp4", "prefix": "videos/"},
+	})
+}
+
+func TestR2MultipartUploadListKey(t *testing.T) {
+	// The key is listed as a prefix, so longer keys are filtered out
+	h := newReplayHarness(t, "", "r2_multipart_upload")
+	rows := h.mustQuery("cloudflare_r2_multipart_upload", []string{"upload_id", "key"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "uploads-bucket"), eq("key", "backups/db.tar"))
+
+	assertRows(t, rows, "upload_id", []map[string]any{
+		{"upload_id": "upload-1", "key": "backups/db.tar"},
+		{"upload_id": "upload-2", "key": "backups/db.tar"},
+	})
+}
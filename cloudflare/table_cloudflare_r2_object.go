@@ -2,13 +2,13 @@ package cloudflare
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"slices"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
-	"github.com/aws/smithy-go"
 	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
@@ -21,6 +21,7 @@ func tableCloudflareR2Object(ctx context.Context) *plugin.Table {
 		Name:             "cloudflare_r2_object",
 		Description:      "List Cloudflare R2 Objects by bucket name",
 		DefaultTransform: transform.FromCamel().NullIfZero(),
+		Tags:             s3APITags(),
 		List: &plugin.ListConfig{
 			Hydrate: listR2Objects,
 			KeyColumns: plugin.KeyColumnSlice{
@@ -28,6 +29,10 @@ func tableCloudflareR2Object(ctx context.Context) *plugin.Table {
 				{Name: "bucket", Require: plugin.Required},
 				{Name: "key", Require: plugin.Optional},
 				{Name: "prefix", Require: plugin.Optional},
+				{Name: "delimiter", Require: plugin.Optional},
+				{Name: "start_after", Require: plugin.Optional},
+				{Name: "presign_expiry_seconds", Require: plugin.Optional},
+				{Name: "jurisdiction", Require: plugin.Optional},
 			},
 		},
 		Columns: commonColumns([]*plugin.Column{
@@ -37,6 +42,12 @@ func tableCloudflareR2Object(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Object.Key"),
 			},
+			{
+				Name:        "is_prefix",
+				Description: "True if the row is a common prefix rolled up by the delimiter (i.e. a directory) rather than an object.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("IsPrefix"),
+			},
 			{
 				Name:        "last_modified",
 				Description: "Specifies the time when the object is last modified.",
@@ -121,6 +132,19 @@ func tableCloudflareR2Object(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_JSON,
 				Hydrate:     getR2ObjectData,
 			},
+			{
+				Name:        "presigned_url",
+				Description: "A presigned URL that can be used to download the object without credentials until it expires.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getR2ObjectPresignedURL,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "presign_expiry_seconds",
+				Description: "How long presigned_url is valid for, in seconds. Defaults to 900 (15 minutes), and may be up to 604800 (7 days).",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromQual("presign_expiry_seconds"),
+			},
 			{
 				Name:        "owner",
 				Description: "The owner of the object",
@@ -153,14 +177,35 @@ func tableCloudflareR2Object(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromQual("prefix"),
 			},
+			{
+				Name:        "delimiter",
+				Description: "The character used to group keys into common prefixes, e.g. \"/\".",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("delimiter"),
+			},
+			{
+				Name:        "start_after",
+				Description: "The key to start listing after.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("start_after"),
+			},
+			{
+				Name:        "jurisdiction",
+				Description: "The jurisdiction of the container bucket, one of default, eu or fedramp.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Jurisdiction"),
+			},
 		}),
 	}
 }
 
 type s3ObjectMetadata = struct {
 	types.Object
-	AccountID *string
-	Bucket    *string
+	AccountID    *string
+	Bucket       *string
+	Jurisdiction *string
+	// Set for the common prefixes returned when listing with a delimiter
+	IsPrefix bool
 }
 
 //// LIST FUNCTION
@@ -176,7 +221,7 @@ func listR2Objects(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 	}
 
 	// get R2 client
-	conn, err := getR2Client(ctx, d, accountID)
+	conn, jurisdiction, err := getR2BucketClient(ctx, d, accountID, bucketName)
 	if err != nil {
 		logger.Error("cloudflare_r2_object.listR2Objects", "R2 client error", err)
 		return nil, err
@@ -194,6 +239,25 @@ func listR2Objects(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 	if prefix != "" {
 		input.Prefix = aws.String(prefix)
 	}
+	if delimiter := d.EqualsQualString("delimiter"); delimiter != "" {
+		input.Delimiter = aws.String(delimiter)
+	}
+	if startAfter := d.EqualsQualString("start_after"); startAfter != "" {
+		input.StartAfter = aws.String(startAfter)
+	}
+
+	// Reduce the page size if a limit is set. R2 returns at most 1000 keys
+	// per page regardless.
+	maxKeys := int32(1000)
+	if d.QueryContext.Limit != nil {
+		// Compare before narrowing, so a limit beyond the int32 range
+		// cannot wrap around
+		limit := *d.QueryContext.Limit
+		if limit < int64(maxKeys) {
+			maxKeys = int32(limit)
+		}
+	}
+	input.MaxKeys = maxKeys
 
 	// if key is provided in qual, use key as prefix
 	// also, set the max limit to 1 for exact match
@@ -208,30 +272,84 @@ func listR2Objects(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDa
 		input.FetchOwner = true
 	}
 
-	object, err := conn.ListObjectsV2(ctx, input)
-	if err != nil {
-		var a smithy.APIError
-		if errors.As(err, &a) {
-			if a.ErrorCode() == "InvalidBucketName" {
-				return nil, nil
+	err = listR2ObjectPages(ctx, conn, input, func(output *s3.ListObjectsV2Output) bool {
+		for _, i := range output.Contents {
+			d.StreamListItem(ctx, &s3ObjectMetadata{
+				Object:       i,
+				AccountID:    aws.String(accountID),
+				Bucket:       aws.String(bucketName),
+				Jurisdiction: aws.String(jurisdiction),
+			})
+
+			// context may get cancelled due to manual cancellation or if the limit has been reached
+			if d.RowsRemaining(ctx) == 0 {
+				return false
 			}
 		}
-		logger.Error("cloudflare_r2_object.getR2Object", "api_error", err)
-		return nil, err
-	}
 
-	for _, i := range object.Contents {
-		d.StreamListItem(ctx, &s3ObjectMetadata{
-			Object:    i,
-			AccountID: aws.String(accountID),
-			Bucket:    aws.String(bucketName),
-		})
+		for _, i := range output.CommonPrefixes {
+			d.StreamListItem(ctx, &s3ObjectMetadata{
+				Object:       types.Object{Key: i.Prefix},
+				AccountID:    aws.String(accountID),
+				Bucket:       aws.String(bucketName),
+				Jurisdiction: aws.String(jurisdiction),
+				IsPrefix:     true,
+			})
+
+			// context may get cancelled due to manual cancellation or if the limit has been reached
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
 
-		// context may get cancelled due to manual cancellation or if the limit has been reached
-		if d.RowsRemaining(ctx) == 0 {
+		// a key lookup only needs the first match
+		return key == ""
+	})
+	if err != nil {
+		if hasS3ErrorCode(err, "InvalidBucketName") {
 			return nil, nil
 		}
+		logger.Error("cloudflare_r2_object.listR2Objects", "api_error", err)
+		return nil, err
 	}
 
 	return nil, nil
 }
+
+//// HYDRATE FUNCTIONS
+
+// getR2ObjectPresignedURL signs a GET request for the object. Signing happens
+// locally, so no request is made to R2.
+func getR2ObjectPresignedURL(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	object := h.Item.(*s3ObjectMetadata)
+	// common prefixes are not objects and cannot be downloaded
+	if object.IsPrefix {
+		return nil, nil
+	}
+
+	// Presigned URLs are valid for 15 minutes by default, S3 allows up to 7 days
+	expiry := int64(900)
+	if q := d.EqualsQuals["presign_expiry_seconds"]; q != nil {
+		expiry = q.GetInt64Value()
+		if expiry < 1 || expiry > 604800 {
+			return nil, fmt.Errorf("presign_expiry_seconds must be between 1 and 604800, got %d", expiry)
+		}
+	}
+
+	conn, err := getR2Client(ctx, d, *object.AccountID, *object.Jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+
+	presignClient := s3.NewPresignClient(conn, s3.WithPresignExpires(time.Duration(expiry)*time.Second))
+	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: object.Bucket,
+		Key:    object.Key,
+	})
+	if err != nil {
+		plugin.Logger(ctx).Error("cloudflare_r2_object.getR2ObjectPresignedURL", "presign_error", err)
+		return nil, err
+	}
+
+	return request.URL, nil
+}
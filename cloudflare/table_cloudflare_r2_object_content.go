@@ -0,0 +1,329 @@
+package cloudflare
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+)
+
+// Formats of structured objects that can be parsed into rows
+const (
+	r2ContentFormatCSV   = "csv"
+	r2ContentFormatTSV   = "tsv"
+	r2ContentFormatJSONL = "jsonl"
+)
+
+//// TABLE DEFINITION
+
+func tableCloudflareR2ObjectContent(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_r2_object_content",
+		Description: "Rows parsed from CSV, TSV and JSON Lines objects in Cloudflare R2.",
+		Tags:        s3APITags(),
+		List: &plugin.ListConfig{
+			Hydrate: listR2ObjectContent,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "account_id", Require: plugin.Required},
+				{Name: "bucket", Require: plugin.Required},
+				{Name: "key", Require: plugin.AnyOf},
+				{Name: "prefix", Require: plugin.AnyOf},
+				{Name: "format", Require: plugin.Optional},
+				{Name: "jurisdiction", Require: plugin.Optional},
+			},
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+		},
+		Columns: commonColumns([]*plugin.Column{
+			{
+				Name:        "key",
+				Description: "The key of the object the row was read from.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "line_number",
+				Description: "The line of the object the row starts on. For CSV and TSV objects, line 1 is the header.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "data",
+				Description: "The row as a JSON object. CSV and TSV values are keyed by the header and are always strings.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "format",
+				Description: "The format the object was parsed as, one of csv, tsv or jsonl. Detected from the key's extension or the content type unless set in the where clause.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "prefix",
+				Description: "The prefix of the keys of the objects to read.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("prefix"),
+			},
+
+			// steampipe standard columns
+			{
+				Name:        "account_id",
+				Description: "ID of the account.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("account_id"),
+			},
+			{
+				Name:        "bucket",
+				Description: "The name of the container bucket of the object.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("bucket"),
+			},
+			{
+				Name:        "jurisdiction",
+				Description: "The jurisdiction of the container bucket, one of default, eu or fedramp.",
+				Type:        proto.ColumnType_STRING,
+			},
+		}),
+	}
+}
+
+type r2ObjectContentRow struct {
+	Key          string
+	LineNumber   int
+	Data         interface{}
+	Format       string
+	Jurisdiction string
+}
+
+//// LIST FUNCTION
+
+func listR2ObjectContent(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	accountID := d.EqualsQualString("account_id")
+	bucket := d.EqualsQualString("bucket")
+	key := d.EqualsQualString("key")
+	prefix := d.EqualsQualString("prefix")
+
+	format := strings.ToLower(d.EqualsQualString("format"))
+	if format != "" && format != r2ContentFormatCSV && format != r2ContentFormatTSV && format != r2ContentFormatJSONL {
+		return nil, fmt.Errorf("invalid format %q, must be one of csv, tsv or jsonl", format)
+	}
+
+	// get R2 client
+	conn, jurisdiction, err := getR2BucketClient(ctx, d, accountID, bucket)
+	if err != nil {
+		logger.Error("cloudflare_r2_object_content.listR2ObjectContent", "R2 client error", err)
+		return nil, err
+	}
+
+	if key != "" {
+		_, err := streamR2ObjectContent(ctx, d, conn, bucket, key, format, jurisdiction, true)
+		if err != nil {
+			logger.Error("cloudflare_r2_object_content.listR2ObjectContent", "api_error", err)
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	// read each object under the prefix in turn
+	paginator := s3.NewListObjectsV2Paginator(conn, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			logger.Error("cloudflare_r2_object_content.listR2ObjectContent", "api_error", err)
+			return nil, err
+		}
+
+		for _, object := range output.Contents {
+			objectKey := aws.ToString(object.Key)
+			// skip folder placeholders
+			if strings.HasSuffix(objectKey, "/") {
+				continue
+			}
+
+			more, err := streamR2ObjectContent(ctx, d, conn, bucket, objectKey, format, jurisdiction, false)
+			if err != nil {
+				logger.Error("cloudflare_r2_object_content.listR2ObjectContent", "key", objectKey, "api_error", err)
+				return nil, err
+			}
+			if !more {
+				return nil, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// streamR2ObjectContent reads an object and streams a row for each record in
+// it. Objects whose format cannot be determined are an error if required is
+// set and skipped otherwise. It returns false once no more rows are wanted.
+func streamR2ObjectContent(ctx context.Context, d *plugin.QueryData, conn *s3.Client, bucket, key, format, jurisdiction string, required bool) (bool, error) {
+	object, err := conn.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return false, err
+	}
+	defer object.Body.Close()
+
+	if format == "" {
+		format = r2ObjectContentFormat(key, aws.ToString(object.ContentType))
+		if format == "" {
+			if required {
+				return false, fmt.Errorf("cannot determine the format of %s from its extension or content type, set format to csv, tsv or jsonl", key)
+			}
+			plugin.Logger(ctx).Debug("cloudflare_r2_object_content.streamR2ObjectContent", "skipping object of unknown format", key)
+			return true, nil
+		}
+	}
+
+	body, err := newR2DecompressReader(object.Body, r2ObjectCompression(aws.ToString(object.ContentEncoding), key))
+	if err != nil {
+		return false, fmt.Errorf("decompressing %s: %w", key, err)
+	}
+	defer body.Close()
+
+	more := true
+	stream := func(lineNumber int, data interface{}) bool {
+		d.StreamListItem(ctx, r2ObjectContentRow{
+			Key:          key,
+			LineNumber:   lineNumber,
+			Data:         data,
+			Format:       format,
+			Jurisdiction: jurisdiction,
+		})
+
+		// Context may get cancelled due to manual cancellation or if the limit has been reached
+		more = d.RowsRemaining(ctx) != 0
+		return more
+	}
+
+	if format == r2ContentFormatJSONL {
+		err = parseJSONLines(body, stream)
+	} else {
+		err = parseDelimited(body, format, stream)
+	}
+	if err != nil {
+		return false, fmt.Errorf("parsing %s: %w", key, err)
+	}
+	return more, nil
+}
+
+// r2ObjectContentFormat works out the format of an object from its key, once
+// any compression extension is removed, or else its content type.
+func r2ObjectContentFormat(key string, contentType string) string {
+	key = strings.ToLower(key)
+	if ext := path.Ext(key); ext == ".gz" || ext == ".gzip" || ext == ".zst" || ext == ".zstd" {
+		key = strings.TrimSuffix(key, ext)
+	}
+	switch path.Ext(key) {
+	case ".csv":
+		return r2ContentFormatCSV
+	case ".tsv", ".tab":
+		return r2ContentFormatTSV
+	case ".jsonl", ".ndjson":
+		return r2ContentFormatJSONL
+	}
+
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "text/csv":
+		return r2ContentFormatCSV
+	case "text/tab-separated-values":
+		return r2ContentFormatTSV
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return r2ContentFormatJSONL
+	}
+	return ""
+}
+
+// parseDelimited parses CSV or TSV data whose first record is a header,
+// calling stream with each following record keyed by the header. Fields
+// beyond the header are keyed by their position, e.g. "column_4". Parsing
+// stops when stream returns false.
+func parseDelimited(r io.Reader, format string, stream func(lineNumber int, data interface{}) bool) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	if format == r2ContentFormatTSV {
+		reader.Comma = '\t'
+		reader.LazyQuotes = true
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Drop a leading byte order mark written by spreadsheet exports
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		row := make(map[string]interface{}, len(record))
+		for i, value := range record {
+			name := fmt.Sprintf("column_%d", i+1)
+			if i < len(header) && header[i] != "" {
+				name = header[i]
+			}
+			row[name] = value
+		}
+		line, _ := reader.FieldPos(0)
+		if !stream(line, row) {
+			return nil
+		}
+	}
+}
+
+// parseJSONLines parses newline delimited JSON, calling stream with each
+// value. Blank lines are skipped. Parsing stops when stream returns false.
+func parseJSONLines(r io.Reader, stream func(lineNumber int, data interface{}) bool) error {
+	// Read whole lines rather than using a bufio.Scanner, whose token limit
+	// is easily exceeded by large records
+	reader := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			var data interface{}
+			if err := json.Unmarshal(trimmed, &data); err != nil {
+				return fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			if !stream(lineNumber, data) {
+				return nil
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cloudflare
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestR2ObjectContentListPrefix(t *testing.T) {
+	// The gzipped export is decompressed, the folder placeholder and the
+	// _SUCCESS marker are skipped, and quoted fields may span lines
+	h := newReplayHarness(t, "", "r2_object_content")
+	rows := h.mustQuery("cloudflare_r2_object_content", []string{"key", "line_number", "data", "format", "prefix", "bucket"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "datasets"), eq("prefix", "exports/"))
+
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	byLine := map[string]map[string]any{}
+	for _, row := range rows {
+		byLine[fmt.Sprintf("%s:%v", row["key"], row["line_number"])] = row
+	}
+	for line, want := range map[string]map[string]any{
+		"exports/2024-01.csv:2":    {"data": map[string]any{"id": "1", "name": "alice", "score": "10"}, "format": "csv", "prefix": "exports/", "bucket": "datasets"},
+		"exports/2024-01.csv:3":    {"data": map[string]any{"id": "2", "name": "bob\nsmith", "score": "20"}},
+		"exports/2024-02.csv.gz:2": {"data": map[string]any{"id": "3", "name": "carol", "score": "30"}, "format": "csv"},
+	} {
+		row, ok := byLine[line]
+		if !ok {
+			t.Errorf("missing row %s", line)
+			continue
+		}
+		for column, value := range want {
+			assertValue(t, line+" column "+column, row[column], value)
+		}
+	}
+}
+
+func TestR2ObjectContentListKey(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object_content")
+
+	// JSON Lines detected from the content type, with blank lines skipped
+	rows := h.mustQuery("cloudflare_r2_object_content", []string{"key", "line_number", "data", "format"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "datasets"), eq("key", "events.log"))
+	assertRows(t, rows, "line_number", []map[string]any{
+		{"line_number": 1, "data": map[string]any{"level": "info", "msg": "start"}, "format": "jsonl"},
+		{"line_number": 3, "data": map[string]any{"level": "error", "msg": "boom"}},
+	})
+
+	// TSV detected from the extension, with unnamed extra fields
+	rows = h.mustQuery("cloudflare_r2_object_content", []string{"line_number", "data", "format"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "datasets"), eq("key", "report.tsv"))
+	assertRows(t, rows, "line_number", []map[string]any{
+		{"line_number": 2, "data": map[string]any{"host": "example.com", "status": "200"}, "format": "tsv"},
+		{"line_number": 3, "data": map[string]any{"host": "example.net", "status": "503", "column_3": "extra"}},
+	})
+
+	// The format can be set when it cannot be detected
+	rows = h.mustQuery("cloudflare_r2_object_content", []string{"line_number", "data", "format"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "datasets"), eq("key", "notes.txt"), eq("format", "jsonl"))
+	assertRows(t, rows, "line_number", []map[string]any{
+		{"line_number": 1, "data": map[string]any{"note": 1}, "format": "jsonl"},
+	})
+
+	_, err := h.query("cloudflare_r2_object_content", []string{"data"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "datasets"), eq("key", "notes.txt")}, 0)
+	if err == nil || !strings.Contains(err.Error(), "cannot determine the format of notes.txt") {
+		t.Errorf("expected an unknown format error, got %v", err)
+	}
+
+	// A missing object has no rows
+	rows = h.mustQuery("cloudflare_r2_object_content", []string{"data"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "datasets"), eq("key", "missing.csv"))
+	assertRows(t, rows, "data", nil)
+}
+
+func TestR2ObjectContentListLimit(t *testing.T) {
+	// The first export satisfies the limit, so the second is never read
+	h := newReplayHarness(t, "", "r2_object_content")
+	rows, err := h.query("cloudflare_r2_object_content", []string{"key", "data"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "datasets"), eq("prefix", "exports/")}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 1 {
+		t.Errorf("got %d rows, want 1", len(rows))
+	}
+	if n := h.server.requestCount("GET", "/r2/01a7362d577a6c3019a474fd6f485823/datasets/exports/2024-02.csv.gz"); n != 0 {
+		t.Errorf("read the second export %d times, want 0", n)
+	}
+}
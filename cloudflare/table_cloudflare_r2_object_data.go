@@ -3,7 +3,11 @@ package cloudflare
 import (
 	"context"
 	"encoding/base64"
-	"io"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -21,12 +25,17 @@ func tableCloudflareR2ObjectData(ctx context.Context) *plugin.Table {
 		Name:             "cloudflare_r2_object_data",
 		Description:      "List content of specific Cloudflare R2 objects by bucket name",
 		DefaultTransform: transform.FromCamel().NullIfZero(),
+		Tags:             s3APITags(),
 		Get: &plugin.GetConfig{
 			Hydrate: getR2ObjectData,
 			KeyColumns: plugin.KeyColumnSlice{
 				{Name: "account_id", Require: plugin.Required},
 				{Name: "bucket", Require: plugin.Required},
 				{Name: "key", Require: plugin.Required},
+				{Name: "range", Require: plugin.Optional},
+				{Name: "offset", Require: plugin.Optional},
+				{Name: "length", Require: plugin.Optional},
+				{Name: "jurisdiction", Require: plugin.Optional},
 			},
 		},
 		Columns: commonColumns([]*plugin.Column{
@@ -228,7 +237,31 @@ func tableCloudflareR2ObjectData(ctx context.Context) *plugin.Table {
 				Name:        "data",
 				Description: "The raw bytes of the object as a string. An UTF8 encoded string is sent, if the bytes entirely consists of valid UTF8 runes, an UTF8 is sent otherwise the bas64 encoding of the bytes is sent.",
 				Type:        proto.ColumnType_STRING,
-				Transform:   transform.FromField("Body").Transform(parseBody),
+				Transform:   transform.FromField("Data").Transform(parseBody),
+			},
+			{
+				Name:        "decompressed",
+				Description: "True if the data was decompressed, based on the content encoding or the key's file extension (.gz or .zst).",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Decompressed"),
+			},
+			{
+				Name:        "range",
+				Description: "The range of bytes to read, as an HTTP Range header value, e.g. \"bytes=0-1023\".",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromQual("range"),
+			},
+			{
+				Name:        "offset",
+				Description: "The offset of the first byte to read.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromQual("offset"),
+			},
+			{
+				Name:        "length",
+				Description: "The number of bytes to read.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromQual("length"),
 			},
 
 			// steampipe standard columns
@@ -250,61 +283,162 @@ func tableCloudflareR2ObjectData(ctx context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromQual("bucket"),
 			},
+			{
+				Name:        "jurisdiction",
+				Description: "The jurisdiction of the container bucket, one of default, eu or fedramp.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Jurisdiction"),
+			},
 		}),
 	}
 }
 
 type s3ObjectContent struct {
 	s3.GetObjectOutput
-	AccountID *string
-	Bucket    *string
+	AccountID    *string
+	Bucket       *string
+	Jurisdiction *string
+	Data         []byte
+	Decompressed bool
 }
 
 //// HYDRATE FUNCTIONS
 
 func getR2ObjectData(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 
-	var accountID, bucket, key *string
+	var accountID, bucket, key, jurisdiction *string
+	var conn *s3.Client
+	var err error
 	if h.Item != nil {
 		data := h.Item.(*s3ObjectMetadata)
+		// common prefixes are not objects and have no data to fetch
+		if data.IsPrefix {
+			return nil, nil
+		}
 		accountID = data.AccountID
 		bucket = data.Bucket
 		key = data.Key
+		jurisdiction = data.Jurisdiction
+
+		// get R2 client for the jurisdiction the object was listed from
+		conn, err = getR2Client(ctx, d, *accountID, *jurisdiction)
 	} else {
 		accountID = aws.String(d.EqualsQualString("account_id"))
 		bucket = aws.String(d.EqualsQualString("bucket"))
 		key = aws.String(d.EqualsQualString("key"))
-	}
 
-	// get R2 client
-	conn, err := getR2Client(ctx, d, *accountID)
+		// get R2 client
+		var bucketJurisdiction string
+		conn, bucketJurisdiction, err = getR2BucketClient(ctx, d, *accountID, *bucket)
+		jurisdiction = aws.String(bucketJurisdiction)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// execute list call
+	// execute get call
 	input := &s3.GetObjectInput{
 		Bucket: bucket,
 		Key:    key,
 	}
 
+	// Only the object data table reads the body, optionally limited to a
+	// range of bytes
+	readBody := h.Item == nil && slices.Contains(d.QueryContext.Columns, "data")
+	var start int64
+	if h.Item == nil {
+		var byteRange string
+		byteRange, start, err = getR2ObjectRange(d)
+		if err != nil {
+			return nil, err
+		}
+		if byteRange != "" {
+			input.Range = aws.String(byteRange)
+		}
+	}
+
 	object, err := conn.GetObject(ctx, input)
 	if err != nil {
 		plugin.Logger(ctx).Error("cloudflare_r2_object_data.getR2ObjectData", "api_error", err)
 		return nil, err
 	}
+	defer object.Body.Close()
 
-	return &s3ObjectContent{*object, accountID, bucket}, nil
+	content := &s3ObjectContent{
+		GetObjectOutput: *object,
+		AccountID:       accountID,
+		Bucket:          bucket,
+		Jurisdiction:    jurisdiction,
+	}
+	if !readBody {
+		return content, nil
+	}
+
+	// Refuse to buffer huge objects rather than exhausting memory
+	maxSize := getMaxObjectDataSize(GetConfig(d.Connection))
+	if maxSize > 0 && object.ContentLength > maxSize {
+		return nil, fmt.Errorf("object %s is %d bytes, larger than max_object_data_size of %d bytes. Use the range, offset or length quals to read part of it", *key, object.ContentLength, maxSize)
+	}
+
+	// Compressed data can only be decoded from the start of the stream
+	compression := r2ObjectCompression(aws.ToString(object.ContentEncoding), *key)
+	if start != 0 {
+		compression = ""
+	}
+	content.Data, err = readR2ObjectBody(object.Body, compression, maxSize, input.Range != nil)
+	if err != nil {
+		return nil, fmt.Errorf("reading object %s: %w", *key, err)
+	}
+	content.Decompressed = compression != ""
+
+	return content, nil
+}
+
+// getR2ObjectRange builds the HTTP Range header for the range, offset and
+// length quals, and returns the offset of the first byte it reads. Suffix
+// ranges such as "bytes=-500" have an unknown start, returned as -1.
+func getR2ObjectRange(d *plugin.QueryData) (string, int64, error) {
+	byteRange := d.EqualsQualString("range")
+	offsetQual, lengthQual := d.EqualsQuals["offset"], d.EqualsQuals["length"]
+
+	if byteRange != "" {
+		if offsetQual != nil || lengthQual != nil {
+			return "", 0, errors.New("range cannot be combined with offset or length")
+		}
+		if !strings.HasPrefix(byteRange, "bytes=") {
+			byteRange = "bytes=" + byteRange
+		}
+		first, _, _ := strings.Cut(strings.TrimPrefix(byteRange, "bytes="), "-")
+		start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+		if err != nil {
+			return byteRange, -1, nil
+		}
+		return byteRange, start, nil
+	}
+
+	if offsetQual == nil && lengthQual == nil {
+		return "", 0, nil
+	}
+	offset := offsetQual.GetInt64Value()
+	if offset < 0 {
+		return "", 0, fmt.Errorf("offset must not be negative, got %d", offset)
+	}
+	if lengthQual == nil {
+		return fmt.Sprintf("bytes=%d-", offset), offset, nil
+	}
+	length := lengthQual.GetInt64Value()
+	if length <= 0 {
+		return "", 0, fmt.Errorf("length must be greater than 0, got %d", length)
+	}
+	return fmt.Sprintf("bytes=%d-%d", offset, offset+length-1), offset, nil
 }
 
 //// TRANSFORM FUNCTIONS
 
 func parseBody(ctx context.Context, d *transform.TransformData) (interface{}, error) {
-	data := d.HydrateItem.(*s3ObjectContent)
-
-	body, err := io.ReadAll(data.Body)
-	if err != nil {
-		return nil, err
+	body, ok := d.Value.([]byte)
+	if !ok || body == nil {
+		return nil, nil
 	}
 
 	if utf8.Valid(body) {
@@ -0,0 +1,133 @@
+package cloudflare
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestR2ObjectDataGet(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object_data", []string{"key", "bucket", "account_id", "data", "content_type", "content_length", "cache_control", "etag", "metadata", "last_modified"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", "index.html"))
+
+	assertRows(t, rows, "key", []map[string]any{
+		{
+			"key":            "index.html",
+			"bucket":         "assets",
+			"account_id":     "01a7362d577a6c3019a474fd6f485823",
+			"data":           "hello world",
+			"content_type":   "text/html",
+			"content_length": 11,
+			"cache_control":  "max-age=60",
+			"etag":           "\"92eb5ffee6ae2fec3ad71c777531578f\"",
+			"metadata":       map[string]any{"owner": "web"},
+			"last_modified":  "2024-01-02T05:20:00Z",
+		},
+	})
+}
+
+// handleCompressedObject serves key from the assets bucket as compressed data
+func handleCompressedObject(t *testing.T, h *replayHarness, key string, data []byte, headers map[string]string) {
+	t.Helper()
+	h.server.handle(fixtureInteraction{
+		Path:       "/r2/01a7362d577a6c3019a474fd6f485823/assets/" + key,
+		Headers:    headers,
+		BodyBase64: base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestR2ObjectDataGetDecompresses(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	// gzip is detected from the content encoding, zstd from the extension
+	handleCompressedObject(t, h, "logs/app.log", gzipData(t, "line 1\nline 2\n"), map[string]string{"Content-Encoding": "gzip"})
+	encoder, _ := zstd.NewWriter(nil)
+	handleCompressedObject(t, h, "logs/app.log.zst", encoder.EncodeAll([]byte("zstd line\n"), nil), nil)
+
+	for key, want := range map[string]string{"logs/app.log": "line 1\nline 2\n", "logs/app.log.zst": "zstd line\n"} {
+		rows := h.mustQuery("cloudflare_r2_object_data", []string{"key", "data", "decompressed"},
+			eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", key))
+		assertRows(t, rows, "key", []map[string]any{
+			{"key": key, "data": want, "decompressed": true},
+		})
+	}
+}
+
+func TestR2ObjectDataGetRange(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	h.server.handle(fixtureInteraction{
+		Path:    "/r2/01a7362d577a6c3019a474fd6f485823/assets/index.html",
+		Status:  206,
+		Headers: map[string]string{"Content-Range": "bytes 6-10/11", "Content-Type": "text/html"},
+		Body:    []byte(`"world"`),
+	})
+
+	rows := h.mustQuery("cloudflare_r2_object_data", []string{"key", "data", "offset", "length", "content_range"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", "index.html"), eq("offset", 6), eq("length", 5))
+
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "index.html", "data": "world", "offset": 6, "length": 5, "content_range": "bytes 6-10/11"},
+	})
+	r := h.server.lastRequest("GET", "/r2/01a7362d577a6c3019a474fd6f485823/assets/index.html")
+	if got := r.Header.Get("Range"); got != "bytes=6-10" {
+		t.Errorf("Range header = %q, want %q", got, "bytes=6-10")
+	}
+
+	// A raw range may leave out the unit
+	h.mustQuery("cloudflare_r2_object_data", []string{"key", "data"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", "index.html"), eq("range", "6-10"))
+	r = h.server.lastRequest("GET", "/r2/01a7362d577a6c3019a474fd6f485823/assets/index.html")
+	if got := r.Header.Get("Range"); got != "bytes=6-10" {
+		t.Errorf("Range header = %q, want %q", got, "bytes=6-10")
+	}
+
+	_, err := h.query("cloudflare_r2_object_data", []string{"key", "data"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", "index.html"), eq("range", "0-1"), eq("offset", 1)}, 0)
+	if err == nil || !strings.Contains(err.Error(), "range cannot be combined with offset or length") {
+		t.Errorf("expected an error combining range and offset, got %v", err)
+	}
+}
+
+func TestR2ObjectDataGetMaxSize(t *testing.T) {
+	h := newReplayHarness(t, "max_object_data_size = 5", "r2_object")
+
+	_, err := h.query("cloudflare_r2_object_data", []string{"key", "data"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", "index.html")}, 0)
+	if err == nil || !strings.Contains(err.Error(), "larger than max_object_data_size of 5 bytes") {
+		t.Errorf("expected a max_object_data_size error, got %v", err)
+	}
+
+	// Metadata can still be read without fetching the data
+	rows := h.mustQuery("cloudflare_r2_object_data", []string{"key", "content_length"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", "index.html"))
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "index.html", "content_length": 11},
+	})
+
+	// The limit also applies to the decompressed data, which can be far
+	// larger than the object itself
+	h = newReplayHarness(t, "max_object_data_size = 100", "r2_object")
+	handleCompressedObject(t, h, "logs/app.log.gz", gzipData(t, strings.Repeat("a", 1000)), nil)
+	_, err = h.query("cloudflare_r2_object_data", []string{"key", "data"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("key", "logs/app.log.gz")}, 0)
+	if err == nil || !strings.Contains(err.Error(), "decompressed data is larger than max_object_data_size of 100 bytes") {
+		t.Errorf("expected a max_object_data_size error for decompressed data, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package cloudflare
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestR2ObjectList(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "account_id", "bucket", "size", "etag", "storage_class", "last_modified"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"))
+
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "css/site.css", "account_id": "01a7362d577a6c3019a474fd6f485823", "bucket": "assets", "size": 1024, "storage_class": "STANDARD", "last_modified": "2024-01-01T05:20:00Z"},
+		{"key": "index.html", "size": 11, "etag": "\"92eb5ffee6ae2fec3ad71c777531578f\""},
+	})
+}
+
+func TestR2ObjectListPrefix(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "prefix", "content_type", "metadata"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("prefix", "css/"))
+
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "css/site.css", "prefix": "css/", "content_type": "text/css"},
+	})
+}
+
+func TestR2ObjectListMissingBucket(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	_, err := h.query("cloudflare_r2_object", []string{"key"}, []testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "missing")}, 0)
+	if err == nil {
+		t.Fatal("expected an error listing a bucket that does not exist")
+	}
+}
+
+func TestR2ObjectListJurisdiction(t *testing.T) {
+	// eu-archive only exists in the EU jurisdiction, which is found by
+	// probing each jurisdiction configured for the account
+	h := newReplayHarness(t, r2JurisdictionConfig, "r2_jurisdiction")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "bucket", "jurisdiction", "size"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "eu-archive"))
+
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "2024/03/backup.tar", "bucket": "eu-archive", "jurisdiction": "eu", "size": 4096},
+	})
+
+	// The bucket's jurisdiction is remembered for the connection
+	h.mustQuery("cloudflare_r2_object", []string{"key"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "eu-archive"))
+	if n := h.server.requestCount("HEAD", "/r2/eu/01a7362d577a6c3019a474fd6f485823/eu-archive"); n != 1 {
+		t.Errorf("probed the EU jurisdiction %d times, want 1", n)
+	}
+}
+
+func TestR2ObjectListPaginates(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "size"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "large"))
+
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "0001.log", "size": 10},
+		{"key": "0002.log", "size": 20},
+		{"key": "0003.log", "size": 30},
+	})
+}
+
+func TestR2ObjectListLimit(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows, err := h.query("cloudflare_r2_object", []string{"key"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "large")}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 1 {
+		t.Errorf("got %d rows, want 1", len(rows))
+	}
+	r := h.server.lastRequest("GET", "/r2/01a7362d577a6c3019a474fd6f485823/large")
+	if r == nil || r.URL.Query().Get("max-keys") != "1" {
+		t.Errorf("expected the limit to be pushed down as max-keys=1")
+	}
+	if n := h.server.requestCount("GET", "/r2/01a7362d577a6c3019a474fd6f485823/large"); n != 1 {
+		t.Errorf("made %d list requests, want 1", n)
+	}
+}
+
+func TestR2ObjectListLargeLimit(t *testing.T) {
+	// A limit beyond the int32 range must not wrap around to a small page
+	h := newReplayHarness(t, "", "r2_object")
+	_, err := h.query("cloudflare_r2_object", []string{"key"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "large")}, 1<<32+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := h.server.lastRequest("GET", "/r2/01a7362d577a6c3019a474fd6f485823/large")
+	if r == nil || r.URL.Query().Get("max-keys") != "1000" {
+		t.Errorf("expected the page size to be capped at max-keys=1000")
+	}
+}
+
+func TestR2ObjectListDelimiter(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "is_prefix", "size", "delimiter", "content_type"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("delimiter", "/"))
+
+	// css/ is rolled up into a single directory row, which has no object data
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "css/", "is_prefix": true, "delimiter": "/", "content_type": nil},
+		{"key": "index.html", "is_prefix": false, "size": 11, "delimiter": "/"},
+	})
+}
+
+func TestR2ObjectListStartAfter(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "start_after"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("start_after", "css/site.css"))
+
+	assertRows(t, rows, "key", []map[string]any{
+		{"key": "index.html", "start_after": "css/site.css"},
+	})
+}
+
+func TestR2ObjectListPresignedURL(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "presigned_url", "presign_expiry_seconds"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("presign_expiry_seconds", 3600))
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	sortRows(rows, "key")
+	presigned, err := url.Parse(rows[1]["presigned_url"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValue(t, "path", presigned.Path, "/r2/01a7362d577a6c3019a474fd6f485823/assets/index.html")
+	assertValue(t, "expiry", presigned.Query().Get("X-Amz-Expires"), "3600")
+	assertValue(t, "presign_expiry_seconds", rows[1]["presign_expiry_seconds"], 3600)
+	if !strings.HasPrefix(presigned.Query().Get("X-Amz-Credential"), "test-access-key/") || presigned.Query().Get("X-Amz-Signature") == "" {
+		t.Errorf("expected a URL signed with the connection's credentials, got %s", presigned)
+	}
+
+	// URLs are signed locally, without fetching the objects
+	if n := h.server.requestCount("GET", "/r2/01a7362d577a6c3019a474fd6f485823/assets/index.html"); n != 0 {
+		t.Errorf("fetched the object %d times, want 0", n)
+	}
+}
+
+func TestR2ObjectListPresignedURLDefaultExpiry(t *testing.T) {
+	h := newReplayHarness(t, "", "r2_object")
+	rows := h.mustQuery("cloudflare_r2_object", []string{"key", "presigned_url"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"))
+
+	presigned, err := url.Parse(rows[0]["presigned_url"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValue(t, "expiry", presigned.Query().Get("X-Amz-Expires"), "900")
+
+	_, err = h.query("cloudflare_r2_object", []string{"key", "presigned_url"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("bucket", "assets"), eq("presign_expiry_seconds", 604801)}, 0)
+	if err == nil || !strings.Contains(err.Error(), "presign_expiry_seconds must be between 1 and 604800") {
+		t.Errorf("expected an expiry error, got %v", err)
+	}
+}
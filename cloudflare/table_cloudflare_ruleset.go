@@ -18,10 +18,11 @@ func tableCloudflareRuleset(ctx context.Context) *plugin.Table {
 		Description: "Cloudflare Rulesets provide a powerful framework for configuring rules to process HTTP requests.",
 		List: &plugin.ListConfig{
 			KeyColumns: []*plugin.KeyColumn{
-				{Name: "account_id", Require: plugin.AnyOf},
-				{Name: "zone_id", Require: plugin.AnyOf},
+				{Name: "account_id", Require: plugin.Optional},
+				{Name: "zone_id", Require: plugin.Optional},
 			},
-			Hydrate: listRulesets,
+			Hydrate:       listRulesets,
+			ParentHydrate: listAccountsAndZones,
 		},
 		Get: &plugin.GetConfig{
 			KeyColumns: []*plugin.KeyColumn{
@@ -29,8 +30,10 @@ func tableCloudflareRuleset(ctx context.Context) *plugin.Table {
 				{Name: "account_id", Require: plugin.AnyOf},
 				{Name: "zone_id", Require: plugin.AnyOf},
 			},
-			ShouldIgnoreError: isNotFoundError([]string{"Invalid ruleset identifier"}),
-			Hydrate:           getRuleset,
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getRuleset,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -40,8 +43,8 @@ func tableCloudflareRuleset(ctx context.Context) *plugin.Table {
 			{Name: "phase", Type: proto.ColumnType_STRING, Description: "The phase of the ruleset."},
 
 			// Query columns for filtering
-			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromQual("account_id"), Description: "The account ID to filter rulesets."},
-			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromQual("zone_id"), Description: "The zone ID to filter rulesets."},
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID").NullIfZero(), Description: "ID of the account an account-level ruleset belongs to."},
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID").NullIfZero(), Description: "ID of the zone a zone-level ruleset belongs to."},
 
 			// Other columns
 			{Name: "description", Type: proto.ColumnType_STRING, Description: "An informative description of the ruleset."},
@@ -50,18 +53,33 @@ func tableCloudflareRuleset(ctx context.Context) *plugin.Table {
 
 			// JSON columns
 			{Name: "rules", Type: proto.ColumnType_JSON, Hydrate: getRuleset, Transform: transform.FromField("Rules"), Description: "The list of rules in the ruleset."},
+			{Name: "rule_expressions", Type: proto.ColumnType_JSON, Hydrate: getRuleset, Transform: transform.FromValue().Transform(rulesetRuleExpressions), Description: "The parsed expression of each rule: whether it is valid, and the fields, IP addresses and hostnames it references. See cloudflare_ruleset_rule for the syntax trees."},
 		}),
 	}
 }
 
+type RulesetInfo struct {
+	AccountID string
+	ZoneID    string
+	rulesets.RulesetListResponse
+}
+
+type RulesetDetails struct {
+	AccountID string
+	ZoneID    string
+	rulesets.RulesetGetResponse
+}
+
 //// LIST FUNCTION
 
-// listRulesets retrieves all rulesets for the specified account_id or zone_id.
+// listRulesets retrieves all rulesets of an account or zone.
 //
 // This function handles both account-level and zone-level rulesets:
 // - Account-level rulesets (account_id)
 // - Zone-level rulesets (zone_id)
-func listRulesets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+//
+// The account or zone is the parent item streamed by listAccountsAndZones.
+func listRulesets(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
 	conn, err := connectV4(ctx, d)
 	if err != nil {
@@ -69,18 +87,24 @@ func listRulesets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 		return nil, err
 	}
 
-	// Get the qualifiers
-	quals := d.EqualsQuals
-	accountID := ""
-	zoneID := ""
+	accountID, zoneID := accountOrZoneScope(d, h)
+	err = iterateRulesets(ctx, conn, accountID, zoneID, func(ruleset RulesetInfo) (bool, error) {
+		d.StreamListItem(ctx, ruleset)
 
-	if quals["account_id"] != nil {
-		accountID = quals["account_id"].GetStringValue()
-	}
-	if quals["zone_id"] != nil {
-		zoneID = quals["zone_id"].GetStringValue()
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		return d.RowsRemaining(ctx) != 0, nil
+	})
+	if err != nil {
+		logger.Error("cloudflare_ruleset.listRulesets", "ListAutoPaging error", err)
+		return nil, err
 	}
 
+	return nil, nil
+}
+
+// iterateRulesets calls fn for each ruleset of the account or zone, until fn
+// returns false or an error.
+func iterateRulesets(ctx context.Context, conn *cloudflare.Client, accountID string, zoneID string, fn func(RulesetInfo) (bool, error)) error {
 	// Build API parameters based on account or zone context
 	input := rulesets.RulesetListParams{}
 	if accountID != "" {
@@ -93,20 +117,16 @@ func listRulesets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 	// Execute paginated API call
 	iter := conn.Rulesets.ListAutoPaging(ctx, input)
 	for iter.Next() {
-		ruleset := iter.Current()
-		d.StreamListItem(ctx, ruleset)
-
-		// Context can be cancelled due to manual cancellation or the limit has been hit
-		if d.RowsRemaining(ctx) == 0 {
-			return nil, nil
+		more, err := fn(RulesetInfo{
+			AccountID:           accountID,
+			ZoneID:              zoneID,
+			RulesetListResponse: iter.Current(),
+		})
+		if err != nil || !more {
+			return err
 		}
 	}
-	if err := iter.Err(); err != nil {
-		logger.Error("cloudflare_ruleset.listRulesets", "ListAutoPaging error", err)
-		return nil, err
-	}
-
-	return nil, nil
+	return iter.Err()
 }
 
 //// GET FUNCTION
@@ -120,6 +140,8 @@ func listRulesets(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateDat
 // Parameters:
 // - id: The ruleset identifier (required)
 // - account_id OR zone_id: The account or zone context (at least one required)
+//
+// When hydrating a listed ruleset, its ID and context are taken from the row.
 func getRuleset(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
 	conn, err := connectV4(ctx, d)
@@ -128,18 +150,19 @@ func getRuleset(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	var rulesetInfo rulesets.RulesetListResponse
-	if h.Item != nil {
-		rulesetInfo = h.Item.(rulesets.RulesetListResponse)
-	}
-
-	quals := d.EqualsQuals
-	rulesetID := quals["id"].GetStringValue()
-	accountID := quals["account_id"].GetStringValue()
-	zoneID := quals["zone_id"].GetStringValue()
-
-	if rulesetID == "" {
+	rulesetID := d.EqualsQualString("id")
+	accountID, zoneID := accountOrZoneScope(d, nil)
+	if rulesetInfo, ok := h.Item.(RulesetInfo); ok {
 		rulesetID = rulesetInfo.ID
+		accountID = rulesetInfo.AccountID
+		zoneID = rulesetInfo.ZoneID
+	} else {
+		// Rulesets of accounts and zones outside the connection's scope are
+		// not returned, as in the list
+		ok, err := accountOrZoneInScope(ctx, d, accountID, zoneID)
+		if err != nil || !ok {
+			return nil, err
+		}
 	}
 
 	// Validate required parameters
@@ -163,5 +186,32 @@ func getRuleset(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData)
 		return nil, err
 	}
 
-	return ruleset, nil
+	return RulesetDetails{
+		AccountID:          accountID,
+		ZoneID:             zoneID,
+		RulesetGetResponse: *ruleset,
+	}, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+// rulesetRuleExpressions parses the expression of each rule of a ruleset
+func rulesetRuleExpressions(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	ruleset, ok := d.Value.(RulesetDetails)
+	if !ok {
+		return nil, nil
+	}
+
+	type ruleExpressionInfo struct {
+		RuleID string `json:"rule_id"`
+		*ruleExpression
+	}
+	expressions := make([]ruleExpressionInfo, 0, len(ruleset.Rules))
+	for _, rule := range ruleset.Rules {
+		expressions = append(expressions, ruleExpressionInfo{
+			RuleID:         rule.ID,
+			ruleExpression: parseRuleExpression(rule.Expression),
+		})
+	}
+	return expressions, nil
 }
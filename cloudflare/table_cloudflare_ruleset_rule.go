@@ -0,0 +1,198 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/rulesets"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableCloudflareRulesetRule(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_ruleset_rule",
+		Description: "Rules of Cloudflare Rulesets, one row per rule, in the order they are evaluated.",
+		List: &plugin.ListConfig{
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "account_id", Require: plugin.Optional},
+				{Name: "zone_id", Require: plugin.Optional},
+				{Name: "ruleset_id", Require: plugin.Optional},
+				{Name: "phase", Require: plugin.Optional},
+				{Name: "kind", Require: plugin.Optional},
+			},
+			Hydrate:       listRulesetRules,
+			ParentHydrate: listAccountsAndZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ID"), Description: "Rule identifier."},
+			{Name: "ruleset_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("RulesetID"), Description: "ID of the ruleset the rule belongs to."},
+			{Name: "position", Type: proto.ColumnType_INT, Description: "The position of the rule in the ruleset, starting from 1. Rules are evaluated in this order."},
+			{Name: "action", Type: proto.ColumnType_STRING, Description: "The action to perform when the rule matches."},
+			{Name: "expression", Type: proto.ColumnType_STRING, Description: "The expression defining which traffic will match the rule."},
+			{Name: "enabled", Type: proto.ColumnType_BOOL, Description: "Whether the rule should be executed."},
+
+			// Ruleset columns
+			{Name: "ruleset_name", Type: proto.ColumnType_STRING, Description: "The human-readable name of the ruleset."},
+			{Name: "phase", Type: proto.ColumnType_STRING, Description: "The phase of the ruleset."},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "The kind of the ruleset (managed, custom, root, or zone)."},
+
+			// Query columns for filtering
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID").NullIfZero(), Description: "ID of the account an account-level ruleset belongs to."},
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID").NullIfZero(), Description: "ID of the zone a zone-level ruleset belongs to."},
+
+			// Other columns
+			{Name: "ref", Type: proto.ColumnType_STRING, Transform: transform.FromField("Ref").NullIfZero(), Description: "The reference of the rule, which stays the same when the rule is updated."},
+			{Name: "description", Type: proto.ColumnType_STRING, Description: "An informative description of the rule."},
+			{Name: "version", Type: proto.ColumnType_STRING, Description: "The version of the rule."},
+			{Name: "last_updated", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("LastUpdated").NullIfZero(), Description: "The timestamp when the rule was last updated."},
+
+			// JSON columns
+			{Name: "action_parameters", Type: proto.ColumnType_JSON, Description: "The parameters configuring the rule's action, e.g. the ruleset an execute rule runs."},
+			{Name: "logging", Type: proto.ColumnType_JSON, Description: "Whether a log is generated when the rule matches, for rules that can override it."},
+			{Name: "ratelimit", Type: proto.ColumnType_JSON, Description: "The rate limiting configuration of the rule, for rate limiting rules."},
+
+			// Parsed expression columns, null if the rule has no expression
+			{Name: "expression_valid", Type: proto.ColumnType_BOOL, Transform: transform.FromField("ParsedExpression.Valid"), Description: "True if the expression could be parsed."},
+			{Name: "expression_error", Type: proto.ColumnType_STRING, Transform: transform.FromField("ParsedExpression.Error").NullIfZero(), Description: "The syntax error found in the expression, with its position."},
+			{Name: "expression_ast", Type: proto.ColumnType_JSON, Transform: transform.FromField("ParsedExpression.AST"), Description: "The syntax tree of the expression. Each node has a type, such as logical, not, comparison, field, function, list, string, ip, cidr or ip_range."},
+			{Name: "expression_fields", Type: proto.ColumnType_JSON, Transform: transform.FromField("ParsedExpression.Fields"), Description: "The fields the expression references, e.g. ip.src or http.host."},
+			{Name: "expression_ips", Type: proto.ColumnType_JSON, Transform: transform.FromField("ParsedExpression.IPs"), Description: "The IP addresses and CIDRs in the expression. IP ranges are given as the CIDRs covering them."},
+			{Name: "expression_hostnames", Type: proto.ColumnType_JSON, Transform: transform.FromField("ParsedExpression.Hostnames"), Description: "The hostnames the expression matches http.host, or the host of URL fields such as http.request.full_uri, against."},
+		}),
+	}
+}
+
+type RulesetRuleInfo struct {
+	AccountID   string
+	ZoneID      string
+	RulesetID   string
+	RulesetName string
+	Phase       string
+	Kind        string
+	Position    int
+	ID          string
+	Ref         string
+	Action      string
+	Expression  string
+	Enabled     bool
+	Description string
+	Version     string
+	LastUpdated time.Time
+
+	// Taken from the raw response, as the SDK fills in every field of the
+	// typed parameters of the rule's action
+	ActionParameters interface{}
+	Logging          interface{}
+	Ratelimit        interface{}
+
+	ParsedExpression *ruleExpression
+}
+
+// rulesetRuleRawFields are the fields of a rule decoded from the raw response
+type rulesetRuleRawFields struct {
+	ActionParameters interface{} `json:"action_parameters"`
+	Logging          interface{} `json:"logging"`
+	Ratelimit        interface{} `json:"ratelimit"`
+}
+
+//// LIST FUNCTION
+
+// listRulesetRules lists the rulesets of the account or zone streamed by
+// listAccountsAndZones, fetches each one and streams its rules. Rulesets
+// excluded by the ruleset_id, phase or kind quals are not fetched.
+func listRulesetRules(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_ruleset_rule.listRulesetRules", "connection_error", err)
+		return nil, err
+	}
+
+	accountID, zoneID := accountOrZoneScope(d, h)
+	err = iterateRulesets(ctx, conn, accountID, zoneID, func(ruleset RulesetInfo) (bool, error) {
+		if id := d.EqualsQualString("ruleset_id"); id != "" && id != ruleset.ID {
+			return true, nil
+		}
+		if phase := d.EqualsQualString("phase"); phase != "" && phase != string(ruleset.Phase) {
+			return true, nil
+		}
+		if kind := d.EqualsQualString("kind"); kind != "" && kind != string(ruleset.Kind) {
+			return true, nil
+		}
+		if err := streamRulesetRules(ctx, d, conn, ruleset); err != nil {
+			return false, err
+		}
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		return d.RowsRemaining(ctx) != 0, nil
+	})
+	if err != nil {
+		logger.Error("cloudflare_ruleset_rule.listRulesetRules", "api error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// streamRulesetRules fetches a ruleset and streams its rules in order.
+func streamRulesetRules(ctx context.Context, d *plugin.QueryData, conn *cloudflare.Client, ruleset RulesetInfo) error {
+	input := rulesets.RulesetGetParams{}
+	if ruleset.AccountID != "" {
+		input.AccountID = cloudflare.F(ruleset.AccountID)
+	}
+	if ruleset.ZoneID != "" {
+		input.ZoneID = cloudflare.F(ruleset.ZoneID)
+	}
+
+	details, err := conn.Rulesets.Get(ctx, ruleset.ID, input)
+	if err != nil {
+		return err
+	}
+
+	for i, rule := range details.Rules {
+		info := RulesetRuleInfo{
+			AccountID:   ruleset.AccountID,
+			ZoneID:      ruleset.ZoneID,
+			RulesetID:   details.ID,
+			RulesetName: details.Name,
+			Phase:       string(details.Phase),
+			Kind:        string(details.Kind),
+			Position:    i + 1,
+			ID:          rule.ID,
+			Ref:         rule.Ref,
+			Action:      string(rule.Action),
+			Expression:  rule.Expression,
+			Enabled:     rule.Enabled,
+			Description: rule.Description,
+			Version:     rule.Version,
+			LastUpdated: rule.LastUpdated,
+
+			ParsedExpression: parseRuleExpression(rule.Expression),
+		}
+		if raw := rule.JSON.RawJSON(); raw != "" {
+			var fields rulesetRuleRawFields
+			if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+				return fmt.Errorf("decoding rule %s of ruleset %s: %w", rule.ID, details.ID, err)
+			}
+			info.ActionParameters = fields.ActionParameters
+			info.Logging = fields.Logging
+			info.Ratelimit = fields.Ratelimit
+		}
+		d.StreamListItem(ctx, info)
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil
+		}
+	}
+
+	return nil
+}
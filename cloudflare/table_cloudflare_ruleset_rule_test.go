@@ -0,0 +1,153 @@
+package cloudflare
+
+import "testing"
+
+func TestRulesetRuleListByZone(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset_rule", []string{
+		"id", "ruleset_id", "ruleset_name", "phase", "kind", "zone_id", "account_id", "position", "ref", "enabled",
+		"action", "expression", "description", "version", "last_updated", "action_parameters", "logging", "ratelimit",
+	}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	// The managed ruleset has no rules, so only the zone ruleset has rows
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":           "3a03d665bac047339bb530ecb439a90d",
+			"ruleset_id":   "2f2feab2026849078ba485f918791bdc",
+			"ruleset_name": "default",
+			"phase":        "http_request_firewall_custom",
+			"kind":         "zone",
+			"zone_id":      "023e105f4ecef8ad9ca31a8372d0c353",
+			"position":     1,
+			"ref":          "3a03d665bac047339bb530ecb439a90d",
+			"enabled":      true,
+			"action":       "block",
+			"expression":   "ip.src in {192.0.2.0/24}",
+			"description":  "Block bad range",
+			"version":      "1",
+			"last_updated": "2024-01-01T05:20:00Z",
+		},
+	})
+}
+
+func TestRulesetRuleListByAccount(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset_rule", []string{
+		"id", "ruleset_id", "kind", "account_id", "zone_id", "position", "ref", "enabled", "action", "action_parameters", "logging", "ratelimit",
+	}, eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":                "6179ae15870a4bb7b2d480d4843b323c",
+			"ruleset_id":        "4814384a9e5d4991b9815dcfc25d2f1f",
+			"kind":              "managed",
+			"account_id":        "01a7362d577a6c3019a474fd6f485823",
+			"position":          1,
+			"ref":               "6179ae15870a4bb7b2d480d4843b323c",
+			"enabled":           true,
+			"action":            "score",
+			"action_parameters": map[string]any{"increment": 5},
+		},
+		{
+			"id":         "a7d2bd1c77a5467e8f1e4a6a0f9c3b22",
+			"ruleset_id": "b3d06cf2a5a544e29b88b2a7e36b5a8e",
+			"kind":       "custom",
+			"account_id": "01a7362d577a6c3019a474fd6f485823",
+			"position":   2,
+			"ref":        "limit_login",
+			"enabled":    false,
+			"action":     "block",
+			"ratelimit": map[string]any{
+				"characteristics":     []any{"ip.src", "cf.colo.id"},
+				"period":              60,
+				"requests_per_period": 10,
+				"mitigation_timeout":  600,
+			},
+		},
+		{
+			"id":                "c1b8a1d3d3f74e0c9d29c6a0c8d7a5e1",
+			"ruleset_id":        "b3d06cf2a5a544e29b88b2a7e36b5a8e",
+			"kind":              "custom",
+			"account_id":        "01a7362d577a6c3019a474fd6f485823",
+			"position":          1,
+			"ref":               "run_owasp",
+			"enabled":           true,
+			"action":            "execute",
+			"action_parameters": map[string]any{"id": "4814384a9e5d4991b9815dcfc25d2f1f", "overrides": map[string]any{"enabled": false}},
+			"logging":           map[string]any{"enabled": false},
+		},
+	})
+}
+
+func TestRulesetRuleListAllScopes(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "zones", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset_rule", []string{"id", "account_id", "zone_id"}, eq("kind", "custom"))
+
+	// The account and zone come from the ruleset each rule belongs to
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "a7d2bd1c77a5467e8f1e4a6a0f9c3b22", "account_id": "01a7362d577a6c3019a474fd6f485823", "zone_id": nil},
+		{"id": "c1b8a1d3d3f74e0c9d29c6a0c8d7a5e1", "account_id": "01a7362d577a6c3019a474fd6f485823", "zone_id": nil},
+	})
+}
+
+func TestRulesetRuleListByPhase(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset_rule", []string{"id", "phase"},
+		eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("phase", "http_request_firewall_custom"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "a7d2bd1c77a5467e8f1e4a6a0f9c3b22", "phase": "http_request_firewall_custom"},
+		{"id": "c1b8a1d3d3f74e0c9d29c6a0c8d7a5e1", "phase": "http_request_firewall_custom"},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/rulesets/4814384a9e5d4991b9815dcfc25d2f1f"); n != 0 {
+		t.Errorf("fetched the ruleset of another phase %d times, want 0", n)
+	}
+}
+
+func TestRulesetRuleExpressionColumns(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset_rule", []string{
+		"id", "expression_valid", "expression_error", "expression_fields", "expression_ips", "expression_hostnames",
+	}, eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":                   "6179ae15870a4bb7b2d480d4843b323c",
+			"expression_valid":     true,
+			"expression_fields":    []any{"http.request.uri.query"},
+			"expression_ips":       []any{},
+			"expression_hostnames": []any{},
+		},
+		{
+			"id":                   "a7d2bd1c77a5467e8f1e4a6a0f9c3b22",
+			"expression_valid":     true,
+			"expression_fields":    []any{"http.request.uri.path", "ip.src"},
+			"expression_ips":       []any{"2001:db8::/32", "203.0.113.0/28"},
+			"expression_hostnames": []any{},
+		},
+		{
+			"id":                   "c1b8a1d3d3f74e0c9d29c6a0c8d7a5e1",
+			"expression_valid":     true,
+			"expression_fields":    []any{"http.host"},
+			"expression_ips":       []any{},
+			"expression_hostnames": []any{"example.com"},
+		},
+	})
+}
+
+func TestRulesetRuleExpressionAST(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset_rule", []string{"id", "expression_ast"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id": "3a03d665bac047339bb530ecb439a90d",
+			"expression_ast": map[string]any{
+				"type":     "comparison",
+				"operator": "in",
+				"lhs":      map[string]any{"type": "field", "name": "ip.src"},
+				"rhs":      map[string]any{"type": "list", "items": []any{map[string]any{"type": "cidr", "value": "192.0.2.0/24"}}},
+			},
+		},
+	})
+}
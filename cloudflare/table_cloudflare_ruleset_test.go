@@ -0,0 +1,142 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRulesetListByZone(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id", "name", "kind", "phase", "zone_id", "account_id", "version", "last_updated", "rules"},
+		eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "2f2feab2026849078ba485f918791bdc", "name": "default", "kind": "zone", "phase": "http_request_firewall_custom", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "account_id": nil, "version": "3", "last_updated": "2024-01-01T05:20:00Z"},
+		{"id": "efb7b8c949ac4650a09736fc376e9aee", "kind": "managed", "phase": "http_request_firewall_managed", "rules": []any{}},
+	})
+	rules := rows[0]["rules"].([]any)
+	if len(rules) != 1 {
+		t.Fatalf("got %d rules, want 1", len(rules))
+	}
+	assertValue(t, "rules[0].expression", rules[0].(map[string]any)["expression"], "ip.src in {192.0.2.0/24}")
+}
+
+func TestRulesetRuleExpressions(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id", "rule_expressions"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id": "2f2feab2026849078ba485f918791bdc",
+			"rule_expressions": []any{
+				map[string]any{
+					"rule_id":   "3a03d665bac047339bb530ecb439a90d",
+					"valid":     true,
+					"fields":    []any{"ip.src"},
+					"ips":       []any{"192.0.2.0/24"},
+					"hostnames": []any{},
+				},
+			},
+		},
+		{"id": "efb7b8c949ac4650a09736fc376e9aee", "rule_expressions": []any{}},
+	})
+}
+
+func TestRulesetGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "ffffffffffffffffffffffffffffffff"))
+
+	assertRows(t, rows, "id", nil)
+}
+
+func TestRulesetListByAccountFollowsCursor(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id", "kind", "account_id", "zone_id"}, eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "4814384a9e5d4991b9815dcfc25d2f1f", "kind": "managed", "account_id": "01a7362d577a6c3019a474fd6f485823", "zone_id": nil},
+		{"id": "b3d06cf2a5a544e29b88b2a7e36b5a8e", "kind": "custom"},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/accounts/01a7362d577a6c3019a474fd6f485823/rulesets"); n != 2 {
+		t.Errorf("made %d list calls, want 2", n)
+	}
+}
+
+func TestRulesetListAllScopes(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "zones", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id", "kind", "account_id", "zone_id"})
+
+	// The token may not read example.org's rulesets, so that zone is skipped
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "2f2feab2026849078ba485f918791bdc", "kind": "zone", "account_id": nil, "zone_id": "023e105f4ecef8ad9ca31a8372d0c353"},
+		{"id": "4814384a9e5d4991b9815dcfc25d2f1f", "kind": "managed", "account_id": "01a7362d577a6c3019a474fd6f485823", "zone_id": nil},
+		{"id": "b3d06cf2a5a544e29b88b2a7e36b5a8e", "kind": "custom", "account_id": "01a7362d577a6c3019a474fd6f485823", "zone_id": nil},
+		{"id": "efb7b8c949ac4650a09736fc376e9aee", "kind": "managed", "account_id": nil, "zone_id": "023e105f4ecef8ad9ca31a8372d0c353"},
+	})
+}
+
+func TestRulesetListAllScopesRules(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "zones", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id", "rules"})
+
+	// Each ruleset is fetched from the account or zone it was listed in
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want 4", len(rows))
+	}
+	sortRows(rows, "id")
+	if rules := rows[1]["rules"].([]any); len(rules) != 1 {
+		t.Errorf("got %d rules for the account managed ruleset, want 1", len(rules))
+	}
+	if rules := rows[2]["rules"].([]any); len(rules) != 2 {
+		t.Errorf("got %d rules for the account custom ruleset, want 2", len(rules))
+	}
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/rulesets/2f2feab2026849078ba485f918791bdc"); n != 1 {
+		t.Errorf("fetched the zone ruleset %d times, want 1", n)
+	}
+}
+
+func TestRulesetListRejectsAccountAndZone(t *testing.T) {
+	h := newReplayHarness(t, "", "ruleset")
+	_, err := h.query("cloudflare_ruleset", []string{"id"},
+		[]testQual{eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353")}, 0)
+	if err == nil || !strings.Contains(err.Error(), "account_id and zone_id cannot both be specified") {
+		t.Errorf("got error %v, want account_id and zone_id to be rejected", err)
+	}
+}
+
+func TestRulesetListQualScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `accounts = ["Some Other Account"]`, "accounts", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id"}, eq("account_id", "01a7362d577a6c3019a474fd6f485823"))
+	assertRows(t, rows, "id", nil)
+
+	h = newReplayHarness(t, `exclude_zones = ["example.com"]`, "zone_details", "ruleset")
+	rows = h.mustQuery("cloudflare_ruleset", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+	assertRows(t, rows, "id", nil)
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/rulesets"); n != 0 {
+		t.Errorf("listed the rulesets of an excluded zone %d times, want 0", n)
+	}
+
+	h = newReplayHarness(t, `zones = ["example.*"]`, "zone_details", "ruleset")
+	rows = h.mustQuery("cloudflare_ruleset", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "2f2feab2026849078ba485f918791bdc"},
+		{"id": "efb7b8c949ac4650a09736fc376e9aee"},
+	})
+}
+
+func TestRulesetGetScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `exclude_zones = ["example.com"]`, "zone_details", "ruleset")
+	rows := h.mustQuery("cloudflare_ruleset", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "2f2feab2026849078ba485f918791bdc"))
+	assertRows(t, rows, "id", nil)
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/rulesets/2f2feab2026849078ba485f918791bdc"); n != 0 {
+		t.Errorf("fetched a ruleset of an excluded zone %d times, want 0", n)
+	}
+
+	h = newReplayHarness(t, `accounts = ["Some Other Account"]`, "accounts", "ruleset")
+	rows = h.mustQuery("cloudflare_ruleset", []string{"id"}, eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("id", "4814384a9e5d4991b9815dcfc25d2f1f"))
+	assertRows(t, rows, "id", nil)
+
+	h = newReplayHarness(t, `accounts = ["Example Account"]`, "accounts", "ruleset")
+	rows = h.mustQuery("cloudflare_ruleset", []string{"id"}, eq("account_id", "01a7362d577a6c3019a474fd6f485823"), eq("id", "4814384a9e5d4991b9815dcfc25d2f1f"))
+	assertRows(t, rows, "id", []map[string]any{{"id": "4814384a9e5d4991b9815dcfc25d2f1f"}})
+}
@@ -0,0 +1,82 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/accounts"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+)
+
+func tableCloudflareSecondaryDNSACL(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_secondary_dns_acl",
+		Description: "A secondary DNS ACL allows an IP range to transfer an account's zones from Cloudflare.",
+		List: &plugin.ListConfig{
+			Hydrate:       listSecondaryDNSACLs,
+			ParentHydrate: listAccount,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "account_id", Require: plugin.Optional},
+			},
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ID"), Description: "ID of the ACL."},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Name of the ACL."},
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID"), Description: "ID of the account the ACL belongs to."},
+
+			// Other columns
+			{Name: "ip_range", Type: proto.ColumnType_STRING, Transform: transform.FromField("IPRange"), Description: "The IP range allowed to transfer zones, as a CIDR of at most /24 for IPv4 and /64 for IPv6."},
+		}),
+	}
+}
+
+type SecondaryDNSACLInfo struct {
+	AccountID string
+	dns.ACL
+}
+
+//// LIST FUNCTION
+
+func listSecondaryDNSACLs(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	account := h.Item.(accounts.Account)
+
+	// Only list ACLs for accounts stated in the input query
+	inputAccountId := d.EqualsQualString("account_id")
+	if inputAccountId != "" && inputAccountId != account.ID {
+		return nil, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_secondary_dns_acl.listSecondaryDNSACLs", "connection error", err)
+		return nil, err
+	}
+
+	input := dns.ZoneTransferACLListParams{
+		AccountID: cloudflare.F(account.ID),
+	}
+
+	iter := conn.DNS.ZoneTransfers.ACLs.ListAutoPaging(ctx, input)
+	for iter.Next() {
+		d.StreamListItem(ctx, SecondaryDNSACLInfo{
+			AccountID: account.ID,
+			ACL:       iter.Current(),
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+	if err := iter.Err(); err != nil {
+		logger.Error("cloudflare_secondary_dns_acl.listSecondaryDNSACLs", "api error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,17 @@
+package cloudflare
+
+import "testing"
+
+func TestSecondaryDNSACLList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "secondary_dns")
+	rows := h.mustQuery("cloudflare_secondary_dns_acl", []string{"id", "name", "account_id", "ip_range"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":         "23ff594956f20c2a721606e94745a8aa",
+			"name":       "my-acl-1",
+			"account_id": "01a7362d577a6c3019a474fd6f485823",
+			"ip_range":   "192.0.2.53/28",
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/query_cache"
+)
+
+func tableCloudflareSecondaryDNSIncoming(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_secondary_dns_incoming",
+		Description: "Incoming zone transfer configuration of a secondary zone, naming the primary nameservers it is transferred from.",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+			},
+			Hydrate:       listSecondaryDNSIncoming,
+			ParentHydrate: listZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID"), Description: "ID of the zone."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Description: "Name of the zone."},
+			{Name: "peers", Type: proto.ColumnType_JSON, Description: "The IDs of the peers the zone is transferred from."},
+
+			// Other columns
+			{Name: "auto_refresh_seconds", Type: proto.ColumnType_INT, Description: "How often the primaries are polled for changes to the SOA serial, in seconds. Notifications from the primaries trigger a transfer sooner."},
+			{Name: "soa_serial", Type: proto.ColumnType_INT, Transform: transform.FromField("SOASerial").NullIfZero(), Description: "The SOA serial of the last transfer."},
+			{Name: "checked_time", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CheckedTime").NullIfZero(), Description: "When the primaries were last checked for changes."},
+			{Name: "created_time", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CreatedTime").NullIfZero(), Description: "When the configuration was created."},
+			{Name: "modified_time", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("ModifiedTime").NullIfZero(), Description: "When the configuration was last modified."},
+		}),
+	}
+}
+
+type SecondaryDNSIncomingInfo struct {
+	ZoneID   string
+	ZoneName string
+	dns.ZoneTransferIncomingGetResponse
+}
+
+//// LIST FUNCTION
+
+func listSecondaryDNSIncoming(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	zone := h.Item.(zones.Zone)
+
+	// Only list configurations for zones stated in the input query
+	inputZoneId := d.EqualsQualString("zone_id")
+	if inputZoneId != "" && inputZoneId != zone.ID {
+		return nil, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_secondary_dns_incoming.listSecondaryDNSIncoming", "connection error", err)
+		return nil, err
+	}
+
+	incoming, err := conn.DNS.ZoneTransfers.Incoming.Get(ctx, dns.ZoneTransferIncomingGetParams{ZoneID: cloudflare.F(zone.ID)})
+	if err != nil {
+		// Zones that are not secondary zones have no incoming configuration
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		logger.Error("cloudflare_secondary_dns_incoming.listSecondaryDNSIncoming", "api error", err)
+		return nil, err
+	}
+
+	d.StreamListItem(ctx, SecondaryDNSIncomingInfo{
+		ZoneID:                          zone.ID,
+		ZoneName:                        zone.Name,
+		ZoneTransferIncomingGetResponse: *incoming,
+	})
+
+	return nil, nil
+}
@@ -0,0 +1,24 @@
+package cloudflare
+
+import "testing"
+
+func TestSecondaryDNSIncomingList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "secondary_dns")
+	rows := h.mustQuery("cloudflare_secondary_dns_incoming", []string{
+		"zone_id", "zone_name", "peers", "auto_refresh_seconds", "soa_serial", "checked_time", "created_time", "modified_time",
+	})
+
+	// example.com is not a secondary zone, so it has no row
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id":              "9a7806061c88ada191ed06f989cc3dac",
+			"zone_name":            "example.org",
+			"peers":                []any{"23ff594956f20c2a721606e94745a8aa", "3a8c0da6f8f2a1c4bf0a5b0ce7e20d41"},
+			"auto_refresh_seconds": 86400,
+			"soa_serial":           2024030101,
+			"checked_time":         "2024-03-02T05:20:00Z",
+			"created_time":         "2024-03-01T05:20:00Z",
+			"modified_time":        "2024-03-01T06:00:00Z",
+		},
+	})
+}
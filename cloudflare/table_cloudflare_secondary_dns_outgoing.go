@@ -0,0 +1,82 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/query_cache"
+)
+
+func tableCloudflareSecondaryDNSOutgoing(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_secondary_dns_outgoing",
+		Description: "Outgoing zone transfer configuration of a primary zone, naming the secondary nameservers it is transferred to.",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+			},
+			Hydrate:       listSecondaryDNSOutgoing,
+			ParentHydrate: listZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID"), Description: "ID of the zone."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Description: "Name of the zone."},
+			{Name: "peers", Type: proto.ColumnType_JSON, Description: "The IDs of the peers the zone is transferred to."},
+
+			// Other columns
+			{Name: "soa_serial", Type: proto.ColumnType_INT, Transform: transform.FromField("SOASerial").NullIfZero(), Description: "The SOA serial of the last transfer."},
+			{Name: "checked_time", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CheckedTime").NullIfZero(), Description: "When the zone was last checked for changes to transfer."},
+			{Name: "created_time", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("CreatedTime").NullIfZero(), Description: "When the configuration was created."},
+			{Name: "last_transferred_time", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("LastTransferredTime").NullIfZero(), Description: "When the zone was last transferred to a secondary."},
+		}),
+	}
+}
+
+type SecondaryDNSOutgoingInfo struct {
+	ZoneID   string
+	ZoneName string
+	dns.ZoneTransferOutgoingGetResponse
+}
+
+//// LIST FUNCTION
+
+func listSecondaryDNSOutgoing(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	zone := h.Item.(zones.Zone)
+
+	// Only list configurations for zones stated in the input query
+	inputZoneId := d.EqualsQualString("zone_id")
+	if inputZoneId != "" && inputZoneId != zone.ID {
+		return nil, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_secondary_dns_outgoing.listSecondaryDNSOutgoing", "connection error", err)
+		return nil, err
+	}
+
+	outgoing, err := conn.DNS.ZoneTransfers.Outgoing.Get(ctx, dns.ZoneTransferOutgoingGetParams{ZoneID: cloudflare.F(zone.ID)})
+	if err != nil {
+		// Zones that are not transferred out have no outgoing configuration
+		if isNotFoundError(err) {
+			return nil, nil
+		}
+		logger.Error("cloudflare_secondary_dns_outgoing.listSecondaryDNSOutgoing", "api error", err)
+		return nil, err
+	}
+
+	d.StreamListItem(ctx, SecondaryDNSOutgoingInfo{
+		ZoneID:                          zone.ID,
+		ZoneName:                        zone.Name,
+		ZoneTransferOutgoingGetResponse: *outgoing,
+	})
+
+	return nil, nil
+}
@@ -0,0 +1,23 @@
+package cloudflare
+
+import "testing"
+
+func TestSecondaryDNSOutgoingList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "secondary_dns")
+	rows := h.mustQuery("cloudflare_secondary_dns_outgoing", []string{
+		"zone_id", "zone_name", "peers", "soa_serial", "checked_time", "created_time", "last_transferred_time",
+	})
+
+	// example.org is not transferred out, so it has no row
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id":               "023e105f4ecef8ad9ca31a8372d0c353",
+			"zone_name":             "example.com",
+			"peers":                 []any{"23ff594956f20c2a721606e94745a8aa"},
+			"soa_serial":            2024010201,
+			"checked_time":          "2024-01-02T05:20:00Z",
+			"created_time":          "2024-01-01T05:20:00Z",
+			"last_transferred_time": "2024-01-02T05:00:00Z",
+		},
+	})
+}
@@ -0,0 +1,85 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/accounts"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+)
+
+func tableCloudflareSecondaryDNSPeer(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_secondary_dns_peer",
+		Description: "A secondary DNS peer is a nameserver an account's zones are transferred from, as primary, or notified and transferred to, as secondary.",
+		List: &plugin.ListConfig{
+			Hydrate:       listSecondaryDNSPeers,
+			ParentHydrate: listAccount,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "account_id", Require: plugin.Optional},
+			},
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ID"), Description: "ID of the peer."},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Name of the peer."},
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID"), Description: "ID of the account the peer belongs to."},
+
+			// Other columns
+			{Name: "ip", Type: proto.ColumnType_STRING, Transform: transform.FromField("IP").NullIfZero(), Description: "The IP address of the peer. Transfers are made to and from it, and notifications sent to it."},
+			{Name: "port", Type: proto.ColumnType_INT, Transform: transform.FromField("Port").NullIfZero(), Description: "The port of the peer."},
+			{Name: "ixfr_enable", Type: proto.ColumnType_BOOL, Description: "True if incremental zone transfers (IXFR) are used with the peer, instead of full transfers (AXFR). Only used when the peer is a primary."},
+			{Name: "tsig_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("TSIGID").NullIfZero(), Description: "The ID of the TSIG key transfers with the peer are authenticated with. Null if they are not authenticated."},
+		}),
+	}
+}
+
+type SecondaryDNSPeerInfo struct {
+	AccountID string
+	dns.Peer
+}
+
+//// LIST FUNCTION
+
+func listSecondaryDNSPeers(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	account := h.Item.(accounts.Account)
+
+	// Only list peers for accounts stated in the input query
+	inputAccountId := d.EqualsQualString("account_id")
+	if inputAccountId != "" && inputAccountId != account.ID {
+		return nil, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_secondary_dns_peer.listSecondaryDNSPeers", "connection error", err)
+		return nil, err
+	}
+
+	input := dns.ZoneTransferPeerListParams{
+		AccountID: cloudflare.F(account.ID),
+	}
+
+	iter := conn.DNS.ZoneTransfers.Peers.ListAutoPaging(ctx, input)
+	for iter.Next() {
+		d.StreamListItem(ctx, SecondaryDNSPeerInfo{
+			AccountID: account.ID,
+			Peer:      iter.Current(),
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+	if err := iter.Err(); err != nil {
+		logger.Error("cloudflare_secondary_dns_peer.listSecondaryDNSPeers", "api error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
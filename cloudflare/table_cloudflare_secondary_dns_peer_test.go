@@ -0,0 +1,28 @@
+package cloudflare
+
+import "testing"
+
+func TestSecondaryDNSPeerList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "secondary_dns")
+	rows := h.mustQuery("cloudflare_secondary_dns_peer", []string{"id", "name", "account_id", "ip", "port", "ixfr_enable", "tsig_id"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":          "23ff594956f20c2a721606e94745a8aa",
+			"name":        "my-peer-1",
+			"account_id":  "01a7362d577a6c3019a474fd6f485823",
+			"ip":          "192.0.2.53",
+			"port":        53,
+			"ixfr_enable": false,
+			"tsig_id":     "69cd1e104af3e6ed3cb344f263fd0d5a",
+		},
+		{
+			"id":          "3a8c0da6f8f2a1c4bf0a5b0ce7e20d41",
+			"name":        "my-peer-2",
+			"account_id":  "01a7362d577a6c3019a474fd6f485823",
+			"ip":          "198.51.100.53",
+			"port":        5353,
+			"ixfr_enable": true,
+		},
+	})
+}
@@ -0,0 +1,90 @@
+package cloudflare
+
+import (
+	"context"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/accounts"
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+)
+
+func tableCloudflareSecondaryDNSTSIG(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_secondary_dns_tsig",
+		Description: "A TSIG key authenticates zone transfers and notifications between Cloudflare and a secondary DNS peer.",
+		List: &plugin.ListConfig{
+			Hydrate:       listSecondaryDNSTSIGs,
+			ParentHydrate: listAccount,
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "account_id", Require: plugin.Optional},
+			},
+		},
+		// The secret of the key is deliberately not exposed
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ID"), Description: "ID of the TSIG key."},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Name of the TSIG key, which must match the key name configured on the peer."},
+			{Name: "account_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("AccountID"), Description: "ID of the account the key belongs to."},
+
+			// Other columns
+			{Name: "algo", Type: proto.ColumnType_STRING, Description: "The algorithm of the key, e.g. hmac-sha512."},
+		}),
+	}
+}
+
+type SecondaryDNSTSIGInfo struct {
+	AccountID string
+	ID        string
+	Name      string
+	Algo      string
+}
+
+//// LIST FUNCTION
+
+func listSecondaryDNSTSIGs(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	logger := plugin.Logger(ctx)
+	account := h.Item.(accounts.Account)
+
+	// Only list keys for accounts stated in the input query
+	inputAccountId := d.EqualsQualString("account_id")
+	if inputAccountId != "" && inputAccountId != account.ID {
+		return nil, nil
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		logger.Error("cloudflare_secondary_dns_tsig.listSecondaryDNSTSIGs", "connection error", err)
+		return nil, err
+	}
+
+	input := dns.ZoneTransferTSIGListParams{
+		AccountID: cloudflare.F(account.ID),
+	}
+
+	iter := conn.DNS.ZoneTransfers.TSIGs.ListAutoPaging(ctx, input)
+	for iter.Next() {
+		tsig := iter.Current()
+
+		// Copy the fields so the secret never reaches the query cache
+		d.StreamListItem(ctx, SecondaryDNSTSIGInfo{
+			AccountID: account.ID,
+			ID:        tsig.ID,
+			Name:      tsig.Name,
+			Algo:      tsig.Algo,
+		})
+
+		// Context can be cancelled due to manual cancellation or the limit has been hit
+		if d.RowsRemaining(ctx) == 0 {
+			return nil, nil
+		}
+	}
+	if err := iter.Err(); err != nil {
+		logger.Error("cloudflare_secondary_dns_tsig.listSecondaryDNSTSIGs", "api error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
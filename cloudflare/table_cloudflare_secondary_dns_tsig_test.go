@@ -0,0 +1,28 @@
+package cloudflare
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSecondaryDNSTSIGList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "secondary_dns")
+	rows := h.mustQuery("cloudflare_secondary_dns_tsig", []string{"id", "name", "account_id", "algo"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":         "69cd1e104af3e6ed3cb344f263fd0d5a",
+			"name":       "tsig.customer.cf.",
+			"account_id": "01a7362d577a6c3019a474fd6f485823",
+			"algo":       "hmac-sha512.",
+		},
+	})
+}
+
+func TestSecondaryDNSTSIGHidesSecret(t *testing.T) {
+	for _, column := range tableCloudflareSecondaryDNSTSIG(nil).Columns {
+		if strings.Contains(column.Name, "secret") {
+			t.Errorf("unexpected column %q exposing the TSIG secret", column.Name)
+		}
+	}
+}
@@ -49,6 +49,16 @@ func listUser(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (
 		return nil, err
 	}
 
+	// Account-owned tokens have no user
+	tokenInfo, err := getTokenInfo(ctx, d, h)
+	if err != nil {
+		logger.Error("cloudflare_user.listUser", "token error", err)
+		return nil, err
+	}
+	if tokenInfo.Owner == tokenOwnerAccount {
+		return nil, nil
+	}
+
 	user, err := conn.User.Get(ctx)
 	if err != nil {
 		logger.Error("cloudflare_user.listUser", "User api error", err)
@@ -125,7 +125,7 @@ func tableCloudflareUserAuditLog(ctx context.Context) *plugin.Table {
 	}
 }
 
-func listUserAuditLogs(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+func listUserAuditLogs(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	logger := plugin.Logger(ctx)
 	conn, err := connectV4(ctx, d)
 	if err != nil {
@@ -133,6 +133,16 @@ func listUserAuditLogs(ctx context.Context, d *plugin.QueryData, _ *plugin.Hydra
 		return nil, err
 	}
 
+	// Account-owned tokens have no user, and so no user audit log
+	tokenInfo, err := getTokenInfo(ctx, d, h)
+	if err != nil {
+		logger.Error("cloudflare_user_audit_log.listUserAuditLogs", "token_error", err)
+		return nil, err
+	}
+	if tokenInfo.Owner == tokenOwnerAccount {
+		return nil, nil
+	}
+
 	opts := user.AuditLogListParams{}
 	if d.EqualsQualString("actor_ip") != "" {
 		opts.Actor = cloudflare.F(user.AuditLogListParamsActor{
@@ -0,0 +1,33 @@
+package cloudflare
+
+import "testing"
+
+func TestUserAuditLogList(t *testing.T) {
+	h := newReplayHarness(t, "", "user_audit_log", "token_user")
+	rows := h.mustQuery("cloudflare_user_audit_log", []string{"id", "actor_email", "actor_ip", "actor_type", "owner_id", "when", "new_value", "old_value", "action", "resource"},
+		eq("actor_email", "pam@example.com"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":          "d5b0f326-1232-4452-8858-1089bd7168ef",
+			"actor_email": "pam@example.com",
+			"actor_ip":    "198.41.129.166",
+			"actor_type":  "user",
+			"owner_id":    "023e105f4ecef8ad9ca31a8372d0c353",
+			"when":        "2024-01-01T05:20:00Z",
+			"new_value":   "low",
+			"old_value":   "high",
+			"resource":    map[string]any{"id": "023e105f4ecef8ad9ca31a8372d0c353", "type": "zone"},
+		},
+	})
+	if got := h.server.lastRequest("GET", "/client/v4/user/audit_logs").URL.Query().Get("actor.email"); got != "pam@example.com" {
+		t.Errorf("actor.email filter = %q, want pam@example.com", got)
+	}
+}
+
+func TestUserAuditLogListAccountToken(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "token_account")
+	rows := h.mustQuery("cloudflare_user_audit_log", []string{"id"})
+
+	assertRows(t, rows, "id", nil)
+}
@@ -0,0 +1,31 @@
+package cloudflare
+
+import "testing"
+
+func TestUserList(t *testing.T) {
+	h := newReplayHarness(t, "", "user", "token_user")
+	rows := h.mustQuery("cloudflare_user", []string{"id", "email", "username", "first_name", "last_name", "country", "created_on", "betas", "organizations"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":         "7c5dae5552338874e5053f2534d2767a",
+			"email":      "pam@example.com",
+			"username":   "cfuser12345",
+			"first_name": "Pam",
+			"last_name":  "Beesly",
+			"country":    "US",
+			"created_on": "2014-01-01T05:20:00Z",
+			"betas":      []any{"mirage_forever"},
+		},
+	})
+}
+
+func TestUserListAccountToken(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "token_account")
+	rows := h.mustQuery("cloudflare_user", []string{"id"})
+
+	assertRows(t, rows, "id", nil)
+	if n := h.server.requestCount("GET", "/client/v4/user"); n != 0 {
+		t.Errorf("account-owned token should not fetch the user, made %d calls", n)
+	}
+}
@@ -0,0 +1,12 @@
+package cloudflare
+
+import "testing"
+
+func TestWorkerRouteList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "worker_route")
+	rows := h.mustQuery("cloudflare_worker_route", []string{"id", "pattern", "script", "zone_id", "zone_name"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "023e105f4ecef8ad9ca31a8372d0c353", "pattern": "example.com/*", "script": "this-is_my_script-01", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "zone_name": "example.com"},
+	})
+}
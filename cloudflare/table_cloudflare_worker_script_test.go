@@ -0,0 +1,22 @@
+package cloudflare
+
+import "testing"
+
+func TestWorkerScriptList(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "worker_script")
+	rows := h.mustQuery("cloudflare_worker_script", []string{"id", "account_id", "etag", "has_modules", "usage_model", "created_on", "placement", "tail_consumers", "subdomain"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":          "this-is_my_script-01",
+			"account_id":  "01a7362d577a6c3019a474fd6f485823",
+			"etag":        "ea95132c15732412d22c1476fa83f27a",
+			"has_modules": true,
+			"usage_model": "standard",
+			"created_on":  "2024-01-01T05:20:00Z",
+			"subdomain":   map[string]any{"enabled": true, "previews_enabled": false},
+		},
+	})
+	assertValue(t, "placement.mode", rows[0]["placement"].(map[string]any)["mode"], "smart")
+	assertValue(t, "tail_consumers[0].service", rows[0]["tail_consumers"].([]any)[0].(map[string]any)["service"], "my-log-consumer")
+}
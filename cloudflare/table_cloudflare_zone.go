@@ -27,9 +27,11 @@ func tableCloudflareZone(ctx context.Context) *plugin.Table {
 			Hydrate: listZones,
 		},
 		Get: &plugin.GetConfig{
-			KeyColumns:        plugin.SingleColumn("id"),
-			ShouldIgnoreError: isNotFoundError([]string{"Invalid zone identifier"}),
-			Hydrate:           getZone,
+			KeyColumns: plugin.SingleColumn("id"),
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: shouldIgnoreErrors(isNotFoundError),
+			},
+			Hydrate: getZone,
 		},
 		Columns: commonColumns([]*plugin.Column{
 			// Top columns
@@ -91,6 +93,17 @@ func listZones(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData)
 	iter := conn.Zones.ListAutoPaging(ctx, input)
 	for iter.Next() {
 		zone := iter.Current()
+
+		// Skip zones excluded by the connection's accounts, zones and exclude_zones options
+		inScope, err := zoneInScope(d, zone)
+		if err != nil {
+			logger.Error("cloudflare_zone.listZones", "scope error", err)
+			return nil, err
+		}
+		if !inScope {
+			continue
+		}
+
 		d.StreamListItem(ctx, zone)
 
 		// Context can be cancelled due to manual cancellation or the limit has been hit
@@ -122,7 +135,14 @@ func getZone(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (i
 	if err != nil {
 		return nil, err
 	}
-	return &zone, nil
+
+	// Zones outside the connection's scope are not returned, as in the list
+	ok, err := zoneInScope(d, *zone)
+	if err != nil || !ok {
+		return nil, err
+	}
+	// The hydrates assert h.Item.(zones.Zone), as the list streams values
+	return *zone, nil
 }
 
 func getZoneDNSSEC(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
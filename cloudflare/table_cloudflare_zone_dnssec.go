@@ -0,0 +1,247 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"github.com/turbot/steampipe-plugin-sdk/v6/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin/transform"
+	"github.com/turbot/steampipe-plugin-sdk/v6/query_cache"
+)
+
+// dnssecPendingThreshold is how long DNSSEC can wait for the DS record to be
+// added or removed at the registrar before it is flagged. Registrars usually
+// publish a change well within a day.
+const dnssecPendingThreshold = 48 * time.Hour
+
+func tableCloudflareZoneDNSSEC(ctx context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "cloudflare_zone_dnssec",
+		Description: "DNSSEC status and signing key of a zone, with checks of its DS record.",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.KeyColumnSlice{
+				{Name: "zone_id", Require: plugin.Optional, CacheMatch: query_cache.CacheMatchExact},
+				{Name: "status", Require: plugin.Optional},
+			},
+			Hydrate:       listZoneDNSSEC,
+			ParentHydrate: listZones,
+		},
+		Columns: commonColumns([]*plugin.Column{
+			// Top columns
+			{Name: "zone_id", Type: proto.ColumnType_STRING, Transform: transform.FromField("ZoneID"), Description: "ID of the zone."},
+			{Name: "zone_name", Type: proto.ColumnType_STRING, Description: "Name of the zone."},
+			{Name: "status", Type: proto.ColumnType_STRING, Description: "Status of DNSSEC: active, pending, disabled, pending-disabled or error."},
+
+			// Key columns
+			{Name: "algorithm", Type: proto.ColumnType_INT, Transform: transform.FromField("Algorithm").NullIfZero(), Description: "The number of the algorithm the zone is signed with, e.g. 13 for ECDSA P-256 with SHA-256."},
+			{Name: "key_type", Type: proto.ColumnType_STRING, Transform: transform.FromField("KeyType").NullIfZero(), Description: "The name of the algorithm the zone is signed with, e.g. ECDSAP256SHA256."},
+			{Name: "key_tag", Type: proto.ColumnType_INT, Transform: transform.FromField("KeyTag").NullIfZero(), Description: "The key tag of the key signing key, as reported by Cloudflare."},
+			{Name: "flags", Type: proto.ColumnType_INT, Transform: transform.FromField("Flags").NullIfZero(), Description: "The flags of the DNSKEY record, 257 for a key signing key."},
+			{Name: "public_key", Type: proto.ColumnType_STRING, Transform: transform.FromField("PublicKey").NullIfZero(), Description: "The public key of the DNSKEY record, base64 encoded."},
+
+			// DS columns
+			{Name: "ds", Type: proto.ColumnType_STRING, Transform: transform.FromField("DS").NullIfZero(), Description: "The DS record to add at the registrar."},
+			{Name: "digest_type", Type: proto.ColumnType_INT, Transform: transform.FromField("DigestType").NullIfZero(), Description: "The number of the digest type of the DS record, e.g. 2 for SHA-256."},
+			{Name: "digest_algorithm", Type: proto.ColumnType_STRING, Transform: transform.FromField("DigestAlgorithm").NullIfZero(), Description: "The name of the digest type of the DS record, e.g. SHA256."},
+			{Name: "digest", Type: proto.ColumnType_STRING, Transform: transform.FromField("Digest").NullIfZero(), Description: "The digest of the DS record."},
+
+			// Check columns
+			{Name: "computed_key_tag", Type: proto.ColumnType_INT, Transform: transform.FromField("ComputedKeyTag"), Description: "The key tag worked out from the DNSKEY record."},
+			{Name: "computed_digest", Type: proto.ColumnType_STRING, Transform: transform.FromField("ComputedDigest").NullIfZero(), Description: "The DS digest worked out from the zone name and DNSKEY record, in upper case hex. Null if the digest type is not supported."},
+			{Name: "ds_consistent", Type: proto.ColumnType_BOOL, Transform: transform.FromField("DSConsistent"), Description: "True if the DS record, digest and key tag match the DNSKEY record. Null if there is no key to check."},
+			{Name: "pending_too_long", Type: proto.ColumnType_BOOL, Transform: transform.FromField("PendingTooLong"), Description: "True if DNSSEC has been pending or pending-disabled for more than 48 hours, waiting on a change to the DS record at the registrar."},
+			{Name: "findings", Type: proto.ColumnType_JSON, Transform: transform.FromField("Findings"), Description: "Problems found with the zone's DNSSEC, such as a DS digest that does not match the DNSKEY or a change pending for too long."},
+
+			// Other columns
+			{Name: "dnssec_multi_signer", Type: proto.ColumnType_BOOL, Transform: transform.FromField("DNSSECMultiSigner"), Description: "True if the zone uses multi-signer DNSSEC, with keys from other providers in its DNSKEY set."},
+			{Name: "dnssec_presigned", Type: proto.ColumnType_BOOL, Transform: transform.FromField("DNSSECPresigned"), Description: "True if the zone is transferred in already signed, as a secondary zone."},
+			{Name: "modified_on", Type: proto.ColumnType_TIMESTAMP, Transform: transform.FromField("ModifiedOn").NullIfZero(), Description: "When DNSSEC was last modified, which for pending states is when the change was started."},
+		}),
+	}
+}
+
+type ZoneDNSSECInfo struct {
+	ZoneID   string
+	ZoneName string
+	dns.DNSSEC
+	ComputedKeyTag *int
+	ComputedDigest string
+	DSConsistent   *bool
+	PendingTooLong bool
+	Findings       []string
+}
+
+func listZoneDNSSEC(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	zone := h.Item.(zones.Zone)
+
+	// Only list zones stated in the input query
+	inputZoneId := d.EqualsQualString("zone_id")
+	if inputZoneId != "" && inputZoneId != zone.ID {
+		return nil, nil
+	}
+
+	dnssec, err := getZoneDNSSEC(ctx, d, h)
+	if err != nil {
+		plugin.Logger(ctx).Error("cloudflare_zone_dnssec.listZoneDNSSEC", "DNSSEC api error", err)
+		return nil, err
+	}
+
+	info := newZoneDNSSECInfo(zone, *dnssec.(*dns.DNSSEC), time.Now())
+	if status := d.EqualsQualString("status"); status != "" && status != string(info.Status) {
+		return nil, nil
+	}
+	d.StreamListItem(ctx, info)
+
+	return nil, nil
+}
+
+// newZoneDNSSECInfo checks the zone's DS record against its DNSKEY record
+// and how long a change has been pending as of now.
+func newZoneDNSSECInfo(zone zones.Zone, dnssec dns.DNSSEC, now time.Time) ZoneDNSSECInfo {
+	info := ZoneDNSSECInfo{
+		ZoneID:   zone.ID,
+		ZoneName: zone.Name,
+		DNSSEC:   dnssec,
+		Findings: []string{},
+	}
+
+	switch dnssec.Status {
+	case dns.DNSSECStatusPending, dns.DNSSECStatusPendingDisabled:
+		if pending := now.Sub(dnssec.ModifiedOn); !dnssec.ModifiedOn.IsZero() && pending > dnssecPendingThreshold {
+			info.PendingTooLong = true
+			change := "added at"
+			if dnssec.Status == dns.DNSSECStatusPendingDisabled {
+				change = "removed from"
+			}
+			info.Findings = append(info.Findings, fmt.Sprintf("DNSSEC has been %s for %d days, waiting for the DS record to be %s the registrar", dnssec.Status, int(pending.Hours()/24), change))
+		}
+	case dns.DNSSECStatusError:
+		info.Findings = append(info.Findings, "DNSSEC is in an error state")
+	}
+
+	if dnssec.PublicKey == "" {
+		return info
+	}
+
+	algorithm, _ := strconv.Atoi(dnssec.Algorithm)
+	rdata, err := dnskeyRdata(int(dnssec.Flags), algorithm, dnssec.PublicKey)
+	if err != nil {
+		info.Findings = append(info.Findings, fmt.Sprintf("DNSKEY public key is not valid: %s", err))
+		return info
+	}
+
+	keyTag := dnskeyKeyTag(rdata)
+	info.ComputedKeyTag = &keyTag
+	consistent := true
+	if int(dnssec.KeyTag) != keyTag {
+		consistent = false
+		info.Findings = append(info.Findings, fmt.Sprintf("Key tag %d does not match the DNSKEY, whose key tag is %d", int(dnssec.KeyTag), keyTag))
+	}
+
+	digestType, _ := strconv.Atoi(dnssec.DigestType)
+	if digest, ok := dsDigest(zone.Name, rdata, digestType); ok {
+		info.ComputedDigest = digest
+		if !strings.EqualFold(dnssec.Digest, digest) {
+			consistent = false
+			info.Findings = append(info.Findings, "DS digest does not match the DNSKEY")
+		}
+	} else {
+		info.Findings = append(info.Findings, fmt.Sprintf("DS digest type %s cannot be checked", dnssec.DigestType))
+	}
+
+	// The DS record is what gets published at the registrar, so check it
+	// carries the same key tag, algorithm and digest
+	if dnssec.DS != "" {
+		want := []string{strconv.Itoa(keyTag), dnssec.Algorithm, dnssec.DigestType, strings.ToUpper(dnssec.Digest)}
+		if got := dsRecordFields(dnssec.DS); strings.Join(got, " ") != strings.Join(want, " ") {
+			consistent = false
+			info.Findings = append(info.Findings, "DS record does not match the key tag, algorithm and digest")
+		}
+	}
+
+	info.DSConsistent = &consistent
+	return info
+}
+
+// dnskeyRdata encodes the RDATA of a DNSKEY record: flags, protocol 3,
+// algorithm and public key (RFC 4034 section 2.1).
+func dnskeyRdata(flags int, algorithm int, publicKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(publicKey), ""))
+	if err != nil {
+		return nil, err
+	}
+	rdata := make([]byte, 4, 4+len(key))
+	binary.BigEndian.PutUint16(rdata, uint16(flags))
+	rdata[2] = 3
+	rdata[3] = byte(algorithm)
+	return append(rdata, key...), nil
+}
+
+// dnskeyKeyTag works out the key tag of a DNSKEY record from its RDATA (RFC
+// 4034 appendix B).
+func dnskeyKeyTag(rdata []byte) int {
+	var tag uint32
+	for i, b := range rdata {
+		if i%2 == 0 {
+			tag += uint32(b) << 8
+		} else {
+			tag += uint32(b)
+		}
+	}
+	tag += tag >> 16 & 0xFFFF
+	return int(tag & 0xFFFF)
+}
+
+// dsDigest works out the DS digest of a DNSKEY record, which hashes the
+// owner name in wire format followed by the RDATA (RFC 4034 section 5.1.4).
+// Only the SHA-1, SHA-256 and SHA-384 digest types are supported.
+func dsDigest(owner string, rdata []byte, digestType int) (string, bool) {
+	var data []byte
+	for _, label := range strings.Split(strings.ToLower(strings.TrimSuffix(owner, ".")), ".") {
+		data = append(data, byte(len(label)))
+		data = append(data, label...)
+	}
+	data = append(data, 0)
+	data = append(data, rdata...)
+
+	var sum []byte
+	switch digestType {
+	case 1:
+		digest := sha1.Sum(data)
+		sum = digest[:]
+	case 2:
+		digest := sha256.Sum256(data)
+		sum = digest[:]
+	case 4:
+		digest := sha512.Sum384(data)
+		sum = digest[:]
+	default:
+		return "", false
+	}
+	return strings.ToUpper(hex.EncodeToString(sum)), true
+}
+
+// dsRecordFields returns the key tag, algorithm, digest type and digest of a
+// DS record in presentation format, e.g.
+// "example.com. 3600 IN DS 2371 13 2 1F98...".
+func dsRecordFields(ds string) []string {
+	fields := strings.Fields(ds)
+	for i, field := range fields {
+		if strings.EqualFold(field, "DS") && len(fields) > i+4 {
+			return []string{fields[i+1], fields[i+2], fields[i+3], strings.ToUpper(strings.Join(fields[i+4:], ""))}
+		}
+	}
+	return nil
+}
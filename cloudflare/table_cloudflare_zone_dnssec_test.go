@@ -0,0 +1,120 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v4/dns"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+)
+
+func TestZoneDNSSECList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "zone_dnssec")
+	rows := h.mustQuery("cloudflare_zone_dnssec", []string{
+		"zone_id", "zone_name", "status", "algorithm", "key_type", "key_tag", "flags", "digest_type", "digest",
+		"computed_key_tag", "computed_digest", "ds_consistent", "pending_too_long", "findings",
+	})
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{
+			"zone_id":          "023e105f4ecef8ad9ca31a8372d0c353",
+			"zone_name":        "example.com",
+			"status":           "active",
+			"algorithm":        13,
+			"key_type":         "ECDSAP256SHA256",
+			"key_tag":          16953,
+			"flags":            257,
+			"digest_type":      2,
+			"digest":           "AFF7958770DB6792BD5D485239C72C95F296762586C647BC95CA71E138E69230",
+			"computed_key_tag": 16953,
+			"computed_digest":  "AFF7958770DB6792BD5D485239C72C95F296762586C647BC95CA71E138E69230",
+			"ds_consistent":    true,
+			"pending_too_long": false,
+			"findings":         []any{},
+		},
+		{
+			"zone_id":          "9a7806061c88ada191ed06f989cc3dac",
+			"status":           "pending",
+			"key_tag":          42,
+			"computed_key_tag": 16953,
+			"computed_digest":  "46D9BF2D3516F0BA0F68D059C4584BF73388BE5420B29FB253564B5306B2EF16",
+			"ds_consistent":    false,
+			"pending_too_long": true,
+		},
+	})
+}
+
+func TestZoneDNSSECListByStatus(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "zone_dnssec")
+	rows := h.mustQuery("cloudflare_zone_dnssec", []string{"zone_id", "status"}, eq("status", "pending"))
+
+	assertRows(t, rows, "zone_id", []map[string]any{
+		{"zone_id": "9a7806061c88ada191ed06f989cc3dac", "status": "pending"},
+	})
+}
+
+func TestNewZoneDNSSECInfo(t *testing.T) {
+	// The published DNSSEC key and DS record of cloudflare.com
+	zone := zones.Zone{ID: "1", Name: "cloudflare.com"}
+	var dnssec dns.DNSSEC
+	if err := json.Unmarshal([]byte(`{
+		"status": "active",
+		"algorithm": "13",
+		"digest_type": "2",
+		"digest": "32996839a6d808afe3eb4a795a0e6a7a39a76fc52ff228b22b76f6d63826f2b9",
+		"ds": "cloudflare.com. 3600 IN DS 2371 13 2 32996839A6D808AFE3EB4A795A0E6A7A39A76FC52FF228B22B76F6D63826F2B9",
+		"flags": 257,
+		"key_tag": 2371,
+		"public_key": "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==",
+		"modified_on": "2024-01-01T00:00:00Z"
+	}`), &dnssec); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	info := newZoneDNSSECInfo(zone, dnssec, now)
+	assertValue(t, "computed key tag", info.ComputedKeyTag, 2371)
+	assertValue(t, "computed digest", info.ComputedDigest, "32996839A6D808AFE3EB4A795A0E6A7A39A76FC52FF228B22B76F6D63826F2B9")
+	assertValue(t, "ds consistent", info.DSConsistent, true)
+	assertValue(t, "findings", info.Findings, []string{})
+
+	// A DS record with a different digest than the digest field
+	tampered := dnssec
+	tampered.DS = "cloudflare.com. 3600 IN DS 2371 13 2 0000000000000000000000000000000000000000000000000000000000000000"
+	info = newZoneDNSSECInfo(zone, tampered, now)
+	assertValue(t, "tampered ds consistent", info.DSConsistent, false)
+	assertValue(t, "tampered findings", info.Findings, []string{"DS record does not match the key tag, algorithm and digest"})
+
+	// Disabling that has waited nine days on the registrar
+	pendingDisabled := dnssec
+	pendingDisabled.Status = dns.DNSSECStatusPendingDisabled
+	info = newZoneDNSSECInfo(zone, pendingDisabled, now)
+	assertValue(t, "pending too long", info.PendingTooLong, true)
+	assertValue(t, "pending findings", info.Findings, []string{"DNSSEC has been pending-disabled for 9 days, waiting for the DS record to be removed from the registrar"})
+
+	// Pending for less than the threshold
+	info = newZoneDNSSECInfo(zone, pendingDisabled, dnssec.ModifiedOn.Add(time.Hour))
+	assertValue(t, "recently pending", info.PendingTooLong, false)
+
+	// Without a key there is nothing to check
+	info = newZoneDNSSECInfo(zone, dns.DNSSEC{Status: dns.DNSSECStatusDisabled}, now)
+	assertValue(t, "disabled ds consistent", info.DSConsistent, nil)
+	assertValue(t, "disabled computed key tag", info.ComputedKeyTag, nil)
+}
+
+func TestDSDigest(t *testing.T) {
+	rdata, err := dnskeyRdata(257, 13, "mdsswUyr3DPW132mOi8V9xESWE8jTo0dxCjjnopKl+GqJxpVXckHAeF+KkxLbxILfDLUT0rAK9iUzy1L53eKGQ==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dsDigest("cloudflare.com", rdata, 3); ok {
+		t.Error("GOST digests should not be supported")
+	}
+	sha1Digest, ok := dsDigest("Cloudflare.com.", rdata, 1)
+	assertValue(t, "sha1 supported", ok, true)
+	assertValue(t, "sha1 length", len(sha1Digest), 40)
+	if _, err := dnskeyRdata(257, 13, "not base64!"); err == nil {
+		t.Error("expected an error for a public key that is not base64")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/zones"
@@ -100,10 +99,7 @@ func getZoneSetting(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateD
 	item, err := conn.Zones.Settings.Get(ctx, settingID, zones.SettingGetParams{ZoneID: cloudflare.F(zoneID)})
 	if err != nil {
 		// Some settings might not be available for all zones
-		if strings.Contains(err.Error(), "Undefined zone setting") {
-			return nil, nil
-		}
-		if strings.Contains(err.Error(), "Access denied") {
+		if isNotFoundError(err) || isForbiddenError(err) {
 			return nil, nil
 		}
 		logger.Error("cloudflare_zone_setting.listZoneSettings", "ZoneSetting api error", err)
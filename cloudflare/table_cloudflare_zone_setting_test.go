@@ -0,0 +1,33 @@
+package cloudflare
+
+import "testing"
+
+func TestZoneSettingList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones", "zone_setting")
+	rows := h.mustQuery("cloudflare_zone_setting", []string{"id", "zone_id", "value", "editable", "modified_on"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "always_online", "zone_id": "023e105f4ecef8ad9ca31a8372d0c353", "value": "on", "editable": true, "modified_on": "2024-01-01T05:20:00Z"},
+		{"id": "min_tls_version", "value": "1.2", "modified_on": nil},
+		{"id": "security_header", "editable": false},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/zones/9a7806061c88ada191ed06f989cc3dac/settings"); n != 0 {
+		t.Errorf("settings of a non-matching zone should not be listed, made %d calls", n)
+	}
+}
+
+func TestZoneSettingGet(t *testing.T) {
+	h := newReplayHarness(t, "", "zone_setting")
+	rows := h.mustQuery("cloudflare_zone_setting", []string{"id", "value"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "always_online"))
+
+	assertRows(t, rows, "id", []map[string]any{{"id": "always_online", "value": "on"}})
+}
+
+func TestZoneSettingGetAccessDenied(t *testing.T) {
+	h := newReplayHarness(t, "", "zone_setting")
+	rows := h.mustQuery("cloudflare_zone_setting", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "origin_max_http_version"))
+	assertRows(t, rows, "id", nil)
+
+	rows = h.mustQuery("cloudflare_zone_setting", []string{"id"}, eq("zone_id", "023e105f4ecef8ad9ca31a8372d0c353"), eq("id", "not_a_setting"))
+	assertRows(t, rows, "id", nil)
+}
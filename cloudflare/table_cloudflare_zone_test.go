@@ -0,0 +1,111 @@
+package cloudflare
+
+import "testing"
+
+func TestZoneList(t *testing.T) {
+	h := newReplayHarness(t, "", "zones")
+	rows := h.mustQuery("cloudflare_zone", []string{"id", "name", "status", "paused", "type", "name_servers", "permissions", "created_on"})
+
+	assertRows(t, rows, "name", []map[string]any{
+		{"id": "023e105f4ecef8ad9ca31a8372d0c353", "name": "example.com", "status": "active", "paused": false, "type": "full", "permissions": []any{"#zone:read", "#zone:edit"}, "created_on": "2024-01-01T05:20:00Z"},
+		{"id": "9a7806061c88ada191ed06f989cc3dac", "name": "example.org", "status": "pending", "paused": true, "type": "partial"},
+	})
+}
+
+func TestZoneGet(t *testing.T) {
+	h := newReplayHarness(t, "", "zone_details")
+	rows := h.mustQuery("cloudflare_zone", []string{"id", "name", "account", "name_servers", "development_mode"}, eq("id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":               "023e105f4ecef8ad9ca31a8372d0c353",
+			"name":             "example.com",
+			"name_servers":     []any{"bob.ns.cloudflare.com", "lola.ns.cloudflare.com"},
+			"development_mode": 0,
+		},
+	})
+	assertValue(t, "account.id", rows[0]["account"].(map[string]any)["id"], "01a7362d577a6c3019a474fd6f485823")
+}
+
+func TestZoneGetNotFound(t *testing.T) {
+	h := newReplayHarness(t, "", "zone_details")
+	rows := h.mustQuery("cloudflare_zone", []string{"id"}, eq("id", "ffffffffffffffffffffffffffffffff"))
+
+	assertRows(t, rows, "id", nil)
+}
+
+func TestZoneHydrates(t *testing.T) {
+	// Regional tiered cache and Argo smart routing are unavailable on this
+	// plan, and the zone has no subscription; all three come back empty
+	h := newReplayHarness(t, "", "zone_details")
+	rows := h.mustQuery("cloudflare_zone", []string{
+		"id", "dnssec", "plan", "subscription", "smart_tiered_cache", "regional_tiered_cache", "argo_tiered_caching",
+		"argo_smart_routing", "bot_management", "security_txt", "leaked_credential_check_enabled",
+	}, eq("id", "023e105f4ecef8ad9ca31a8372d0c353"))
+
+	assertRows(t, rows, "id", []map[string]any{
+		{
+			"id":                              "023e105f4ecef8ad9ca31a8372d0c353",
+			"subscription":                    nil,
+			"regional_tiered_cache":           nil,
+			"argo_smart_routing":              nil,
+			"leaked_credential_check_enabled": true,
+		},
+	})
+	row := rows[0]
+	assertValue(t, "dnssec.status", row["dnssec"].(map[string]any)["status"], "active")
+	assertValue(t, "plan[0].legacy_id", row["plan"].([]any)[0].(map[string]any)["legacy_id"], "free")
+	assertValue(t, "smart_tiered_cache.value", row["smart_tiered_cache"].(map[string]any)["value"], "on")
+	assertValue(t, "argo_tiered_caching.value", row["argo_tiered_caching"].(map[string]any)["value"], "off")
+	assertValue(t, "bot_management.fight_mode", row["bot_management"].(map[string]any)["fight_mode"], true)
+	assertValue(t, "security_txt.contact", row["security_txt"].(map[string]any)["contact"], []any{"mailto:security@example.com"})
+}
+
+func TestZoneListPagination(t *testing.T) {
+	h := newReplayHarness(t, "", "zones")
+	h.mustQuery("cloudflare_zone", []string{"id"})
+
+	// Page 1, page 2, then the empty page that ends iteration
+	if n := h.server.requestCount("GET", "/client/v4/zones"); n != 3 {
+		t.Errorf("made %d list calls, want 3", n)
+	}
+}
+
+func TestZoneListScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `zones = ["*.com", "9a7806061c88ada191ed06f989cc3dac"]`, "zones")
+	rows := h.mustQuery("cloudflare_zone", []string{"id", "name"})
+	assertRows(t, rows, "name", []map[string]any{{"name": "example.com"}, {"name": "example.org"}})
+
+	h = newReplayHarness(t, `
+zones         = ["example.*"]
+exclude_zones = ["*.org"]
+`, "zones")
+	rows = h.mustQuery("cloudflare_zone", []string{"id", "name"})
+	assertRows(t, rows, "name", []map[string]any{{"name": "example.com"}})
+
+	h = newReplayHarness(t, `accounts = ["Some Other Account"]`, "zones")
+	rows = h.mustQuery("cloudflare_zone", []string{"id", "name"})
+	assertRows(t, rows, "name", nil)
+}
+
+func TestZoneGetScopedByConnection(t *testing.T) {
+	h := newReplayHarness(t, `exclude_zones = ["example.com"]`, "zone_details")
+	rows := h.mustQuery("cloudflare_zone", []string{"id"}, eq("id", "023e105f4ecef8ad9ca31a8372d0c353"))
+	assertRows(t, rows, "id", nil)
+
+	h = newReplayHarness(t, `zones = ["example.com"]`, "zone_details")
+	rows = h.mustQuery("cloudflare_zone", []string{"id"}, eq("id", "023e105f4ecef8ad9ca31a8372d0c353"))
+	assertRows(t, rows, "id", []map[string]any{{"id": "023e105f4ecef8ad9ca31a8372d0c353"}})
+}
+
+func TestZoneScopeAppliesToChildTables(t *testing.T) {
+	h := newReplayHarness(t, `exclude_zones = ["023e105f4ecef8ad9ca31a8372d0c353"]`, "zones", "dns_record")
+	rows := h.mustQuery("cloudflare_dns_record", []string{"id", "zone_id"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e", "zone_id": "9a7806061c88ada191ed06f989cc3dac"},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/zones/023e105f4ecef8ad9ca31a8372d0c353/dns_records"); n != 0 {
+		t.Errorf("excluded zone should not be queried, made %d calls", n)
+	}
+}
@@ -0,0 +1,100 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/accounts"
+	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+)
+
+// Who the connection's credentials belong to. User tokens and global API keys
+// act as a user and can call the /user endpoints; account-owned tokens belong
+// to a single account and have no user.
+const (
+	tokenOwnerUser    = "user"
+	tokenOwnerAccount = "account"
+)
+
+type TokenInfo struct {
+	Owner     string
+	ID        string // ID of the API token, empty for API key credentials
+	UserID    string // ID of the user, empty for account-owned tokens
+	AccountID string // ID of the owning account for account-owned tokens
+}
+
+// getTokenInfo is called for every row via user_id, so it is fetched once per
+// connection.
+func getTokenInfo(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (*TokenInfo, error) {
+	return callOncePerConnection(ctx, d, "getTokenInfo", time.Hour, func() (*TokenInfo, error) {
+		return getTokenInfoUncached(ctx, d, h)
+	})
+}
+
+// getTokenInfoUncached works out who owns the connection's credentials. User
+// tokens verify against /user/tokens/verify; account-owned tokens only verify
+// against /accounts/{account_id}/tokens/verify for the account that owns them.
+func getTokenInfoUncached(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (*TokenInfo, error) {
+	logger := plugin.Logger(ctx)
+
+	creds, err := getCredentials(GetConfig(d.Connection))
+	if err != nil {
+		return nil, err
+	}
+	// Global API keys always belong to a user
+	if creds.Token == "" {
+		return userTokenInfo(ctx, d, h, "")
+	}
+
+	conn, err := connectV4(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	token, userErr := conn.User.Tokens.Verify(ctx)
+	if userErr == nil {
+		return userTokenInfo(ctx, d, h, token.ID)
+	}
+	// Anything other than a rejection (e.g. a network error) is not a sign
+	// that the token belongs to an account
+	if status := errorStatusCode(userErr); status < 400 || status >= 500 {
+		return nil, userErr
+	}
+
+	// An account-owned token can list the one account it belongs to
+	iter := conn.Accounts.ListAutoPaging(ctx, accounts.AccountListParams{})
+	for iter.Next() {
+		account := iter.Current()
+		token, err := conn.Accounts.Tokens.Verify(ctx, accounts.TokenVerifyParams{AccountID: cloudflare.F(account.ID)})
+		if err != nil {
+			logger.Debug("getTokenInfoUncached", "account", account.ID, "verify error", err)
+			continue
+		}
+		return &TokenInfo{Owner: tokenOwnerAccount, ID: token.ID, AccountID: account.ID}, nil
+	}
+	if err := iter.Err(); err != nil {
+		logger.Debug("getTokenInfoUncached", "list accounts error", err)
+	}
+
+	return nil, fmt.Errorf("API token could not be verified as a user or account token: %w", userErr)
+}
+
+// userTokenInfo returns the token info for credentials that act as a user,
+// with the ID of that user. It runs within getTokenInfo, so the user is
+// fetched once per connection.
+func userTokenInfo(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData, tokenID string) (*TokenInfo, error) {
+	res, err := getUserUncached(ctx, d, h)
+	if err != nil {
+		return nil, err
+	}
+	userDetails, ok := res.(*UserDetails)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T in userTokenInfo, expected UserDetails", res)
+	}
+	if userDetails.ID == "" {
+		return nil, fmt.Errorf("user ID is empty or could not be retrieved")
+	}
+	return &TokenInfo{Owner: tokenOwnerUser, ID: tokenID, UserID: userDetails.ID}, nil
+}
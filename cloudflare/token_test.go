@@ -0,0 +1,41 @@
+package cloudflare
+
+import "testing"
+
+func TestUserIDColumn(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "user", "token_user")
+	rows := h.mustQuery("cloudflare_account", []string{"id", "user_id"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "01a7362d577a6c3019a474fd6f485823", "user_id": "7c5dae5552338874e5053f2534d2767a"},
+	})
+	// Token details are fetched once per connection, not once per row
+	if n := h.server.requestCount("GET", "/client/v4/user/tokens/verify"); n != 1 {
+		t.Errorf("verified the token %d times, want 1", n)
+	}
+}
+
+func TestUserIDColumnAccountToken(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "token_account")
+	rows := h.mustQuery("cloudflare_account", []string{"id", "user_id"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "01a7362d577a6c3019a474fd6f485823", "user_id": nil},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/user"); n != 0 {
+		t.Errorf("account-owned token should not fetch the user, made %d calls", n)
+	}
+}
+
+func TestUserIDColumnFetchesUserOnce(t *testing.T) {
+	h := newReplayHarness(t, "", "accounts", "account_role", "user", "token_user")
+	rows := h.mustQuery("cloudflare_account_role", []string{"id", "user_id"})
+
+	assertRows(t, rows, "id", []map[string]any{
+		{"id": "05784afa30c1afe1440e79d9351c7430", "user_id": "7c5dae5552338874e5053f2534d2767a"},
+		{"id": "3536bcfad5faccb999b47003c79917fb", "user_id": "7c5dae5552338874e5053f2534d2767a"},
+	})
+	if n := h.server.requestCount("GET", "/client/v4/user"); n != 1 {
+		t.Errorf("fetched the user %d times, want 1", n)
+	}
+}
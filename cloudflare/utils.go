@@ -5,22 +5,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 
 	cloudflare4 "github.com/cloudflare/cloudflare-go/v4"
 	"github.com/cloudflare/cloudflare-go/v4/option"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
-
-	"github.com/turbot/steampipe-plugin-sdk/v6/memoize"
 	"github.com/turbot/steampipe-plugin-sdk/v6/plugin"
+	"golang.org/x/time/rate"
 )
 
 type Organization struct {
@@ -52,79 +50,6 @@ type UserDetails struct {
 	Betas                []string       `json:"betas"` // List of beta features enabled.
 }
 
-// Create Cloudflare R2 API client
-func getR2Client(ctx context.Context, d *plugin.QueryData, accountID string) (*s3.Client, error) {
-	sessionCacheKey := fmt.Sprintf("session-v2-%s", accountID)
-	if cachedData, ok := d.ConnectionManager.Cache.Get(sessionCacheKey); ok {
-		return cachedData.(*s3.Client), nil
-	}
-
-	cloudflareConfig := GetConfig(d.Connection)
-	var accessKey, secret string
-
-	if cloudflareConfig.AccessKey != nil {
-		accessKey = *cloudflareConfig.AccessKey
-	}
-
-	if cloudflareConfig.SecretKey != nil {
-		secret = *cloudflareConfig.SecretKey
-	}
-
-	if accessKey == "" || secret == "" {
-		return nil, errors.New("cloudflare R2 API credentials not found. Edit your connection to configure AccessKey and Secret, and then restart Steampipe")
-	}
-
-	r2EndpointResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
-		return aws.Endpoint{
-			URL: fmt.Sprintf("https://%s.r2.cloudflarestorage.com", accountID),
-		}, nil
-	})
-
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithEndpointResolverWithOptions(r2EndpointResolver),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secret, "")),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
-	}
-	client := s3.NewFromConfig(cfg)
-
-	d.ConnectionManager.Cache.Set(sessionCacheKey, client)
-
-	return client, nil
-}
-
-func isNotFoundError(notFoundErrors []string) plugin.ErrorPredicate {
-	return func(err error) bool {
-		errMsg := err.Error()
-		for _, msg := range notFoundErrors {
-			if strings.Contains(errMsg, msg) {
-				return true
-			}
-		}
-		return false
-	}
-}
-
-// if the caching is required other than per connection, build a cache key for the call and use it in Memoize
-// since getUser is a call, caching should be per connection
-var getUserMemoized = plugin.HydrateFunc(getUserUncached).Memoize(memoize.WithCacheKeyFunction(getUserCacheKey))
-
-// Build a cache key for the call to getUser.
-func getUserCacheKey(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
-	key := "getUserInfo"
-	return key, nil
-}
-
-func getUserInfo(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
-	userDetails, err := getUserMemoized(ctx, d, h)
-	if err != nil {
-		return nil, err
-	}
-
-	return userDetails, nil
-}
-
 func getUserUncached(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	conn, err := connectV4(ctx, d)
 	if err != nil {
@@ -177,22 +102,18 @@ func getValueFromInterface(obj interface{}, key string) (interface{}, bool) {
 	return nil, false
 }
 
+// getUserId returns the ID of the user the connection's credentials act as,
+// or null for account-owned tokens. It is taken from the token info, which is
+// fetched once per connection.
 func getUserId(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
-	res, err := getUserInfo(ctx, d, h)
+	tokenInfo, err := getTokenInfo(ctx, d, h)
 	if err != nil {
 		return nil, err
 	}
-
-	userDetails, ok := res.(*UserDetails)
-	if !ok {
-		return nil, fmt.Errorf("unexpected type %T in getUserId, expected UserDetails", res)
-	}
-
-	if userDetails.ID == "" {
-		return nil, fmt.Errorf("user ID is empty or could not be retrieved")
+	if tokenInfo.UserID == "" {
+		return nil, nil
 	}
-
-	return userDetails.ID, nil
+	return tokenInfo.UserID, nil
 }
 
 // getRequestTimeout returns the request timeout from config or environment variables
@@ -214,6 +135,20 @@ func getRequestTimeout(config cloudflareConfig) time.Duration {
 	return defaultTimeout
 }
 
+// getBaseURL returns the Cloudflare API base URL from config or environment variables.
+// An empty string means the SDK default (https://api.cloudflare.com/client/v4/) is used.
+func getBaseURL(config cloudflareConfig) string {
+	if config.BaseURL != nil && *config.BaseURL != "" {
+		return *config.BaseURL
+	}
+
+	if baseURL := os.Getenv("CLOUDFLARE_BASE_URL"); baseURL != "" {
+		return baseURL
+	}
+
+	return ""
+}
+
 // getMaxRetries returns the max retries from config or environment variables
 func getMaxRetries(config cloudflareConfig) int {
 	// Default retries
@@ -233,10 +168,47 @@ func getMaxRetries(config cloudflareConfig) int {
 	return defaultRetries
 }
 
+// getRequestLimiter returns a token bucket limiting API requests per second,
+// or nil if rate limiting is disabled. The default of 4 requests per second
+// matches Cloudflare's global quota of 1200 requests per 5 minutes.
+func getRequestLimiter(config cloudflareConfig) *rate.Limiter {
+	requestsPerSecond := 4.0
+
+	if config.RequestsPerSecond != nil {
+		requestsPerSecond = *config.RequestsPerSecond
+	} else if rpsStr := os.Getenv("CLOUDFLARE_REQUESTS_PER_SECOND"); rpsStr != "" {
+		if rps, err := strconv.ParseFloat(rpsStr, 64); err == nil && rps >= 0 {
+			requestsPerSecond = rps
+		}
+	}
+
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), int(math.Ceil(requestsPerSecond)))
+}
+
 func connectV4(ctx context.Context, d *plugin.QueryData) (*cloudflare4.Client, error) {
-	// Get the config
-	cloudflareConfig := GetConfig(d.Connection)
+	// The client is safe for concurrent use; share one per connection so the
+	// request rate limiter covers every table queried through it
+	cacheKey := fmt.Sprintf("cloudflare-v4-client-%s", d.Connection.Name)
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.(*cloudflare4.Client), nil
+	}
+
+	config := GetConfig(d.Connection)
+	client, err := newV4Client(config, getRequestLimiter(config))
+	if err != nil {
+		return nil, err
+	}
 
+	d.ConnectionManager.Cache.Set(cacheKey, client)
+	return client, nil
+}
+
+// newV4Client creates a v4 API client. Every request waits on the limiter,
+// if there is one.
+func newV4Client(cloudflareConfig cloudflareConfig, limiter *rate.Limiter) (*cloudflare4.Client, error) {
 	// Get timeout and retry configuration
 	requestTimeout := getRequestTimeout(cloudflareConfig)
 	maxRetries := getMaxRetries(cloudflareConfig)
@@ -246,27 +218,64 @@ func connectV4(ctx context.Context, d *plugin.QueryData) (*cloudflare4.Client, e
 	clientOptions = append(clientOptions, option.WithRequestTimeout(requestTimeout))
 	clientOptions = append(clientOptions, option.WithMaxRetries(maxRetries))
 
+	// Throttle requests to stay under the account's API quota
+	if limiter != nil {
+		clientOptions = append(clientOptions, option.WithMiddleware(func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+			if err := limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}))
+	}
+
+	// Point the client at a different API host (e.g. a proxy or a local stand-in) if configured
+	if baseURL := getBaseURL(cloudflareConfig); baseURL != "" {
+		// option.WithBaseURL exits the process on a malformed URL, so validate it first
+		if _, err := url.ParseRequestURI(baseURL); err != nil {
+			return nil, fmt.Errorf("invalid base_url %q: %v", baseURL, err)
+		}
+		clientOptions = append(clientOptions, option.WithBaseURL(baseURL))
+	}
+
+	creds, err := getCredentials(cloudflareConfig)
+	if err != nil {
+		return nil, err
+	}
+	if creds.Token != "" {
+		clientOptions = append(clientOptions, option.WithAPIToken(creds.Token))
+	} else {
+		clientOptions = append(clientOptions, option.WithAPIKey(creds.APIKey))
+		clientOptions = append(clientOptions, option.WithAPIEmail(creds.Email))
+	}
+
+	return cloudflare4.NewClient(clientOptions...), nil
+}
+
+// apiCredentials are the credentials the v4 API client authenticates with:
+// either an API token, or an email and global API key.
+type apiCredentials struct {
+	Token  string
+	Email  string
+	APIKey string
+}
+
+// getCredentials resolves the connection's credentials from config, falling
+// back to the environment variables used by Terraform and flarectl.
+func getCredentials(cloudflareConfig cloudflareConfig) (apiCredentials, error) {
 	// First: check for the token in config
 	if cloudflareConfig.Token != nil {
-		clientOptions = append(clientOptions, option.WithAPIToken(*cloudflareConfig.Token))
-		client := cloudflare4.NewClient(clientOptions...)
-		return client, nil
+		return apiCredentials{Token: *cloudflareConfig.Token}, nil
 	}
 
 	// Second: Email + API Key from config
 	if cloudflareConfig.Email != nil && cloudflareConfig.APIKey != nil {
-		clientOptions = append(clientOptions, option.WithAPIKey(*cloudflareConfig.APIKey))
-		clientOptions = append(clientOptions, option.WithAPIEmail(*cloudflareConfig.Email))
-		client := cloudflare4.NewClient(clientOptions...)
-		return client, nil
+		return apiCredentials{Email: *cloudflareConfig.Email, APIKey: *cloudflareConfig.APIKey}, nil
 	}
 
 	// Third: CLOUDFLARE_API_TOKEN (like Terraform)
 	token, ok := os.LookupEnv("CLOUDFLARE_API_TOKEN")
 	if ok && token != "" {
-		clientOptions = append(clientOptions, option.WithAPIToken(token))
-		client := cloudflare4.NewClient(clientOptions...)
-		return client, nil
+		return apiCredentials{Token: token}, nil
 	}
 
 	// Fourth: CLOUDFLARE_EMAIL / CLOUDFLARE_API_KEY (like Terraform)
@@ -274,19 +283,14 @@ func connectV4(ctx context.Context, d *plugin.QueryData) (*cloudflare4.Client, e
 	if ok && email != "" {
 		key, ok := os.LookupEnv("CLOUDFLARE_API_KEY")
 		if ok && key != "" {
-			clientOptions = append(clientOptions, option.WithAPIKey(key))
-			clientOptions = append(clientOptions, option.WithAPIEmail(email))
-			client := cloudflare4.NewClient(clientOptions...)
-			return client, nil
+			return apiCredentials{Email: email, APIKey: key}, nil
 		}
 	}
 
 	// Fifth: CF_API_TOKEN (like flarectl and Go SDK)
 	token, ok = os.LookupEnv("CF_API_TOKEN")
 	if ok && token != "" {
-		clientOptions = append(clientOptions, option.WithAPIToken(token))
-		client := cloudflare4.NewClient(clientOptions...)
-		return client, nil
+		return apiCredentials{Token: token}, nil
 	}
 
 	// Sixth: CF_EMAIL / CF_API_KEY (like flarectl / Go SDK)
@@ -294,14 +298,11 @@ func connectV4(ctx context.Context, d *plugin.QueryData) (*cloudflare4.Client, e
 	if ok && email != "" {
 		key, ok := os.LookupEnv("CF_API_KEY")
 		if ok && key != "" {
-			clientOptions = append(clientOptions, option.WithAPIKey(key))
-			clientOptions = append(clientOptions, option.WithAPIEmail(email))
-			client := cloudflare4.NewClient(clientOptions...)
-			return client, nil
+			return apiCredentials{Email: email, APIKey: key}, nil
 		}
 	}
 
-	return nil, errors.New("cloudflare API credentials not found; edit your connection configuration file and then restart Steampipe")
+	return apiCredentials{}, errors.New("cloudflare API credentials not found; edit your connection configuration file and then restart Steampipe")
 }
 
 // This function is used to extract the extra field that are not in the part of API response structure.
@@ -356,3 +357,31 @@ func unmarshalBytes(b []byte) (map[string]interface{}, error) {
 	err := json.Unmarshal(b, &m)
 	return m, err
 }
+
+// connectionCallLocks holds a lock per connection and key for
+// callOncePerConnection
+var connectionCallLocks sync.Map
+
+// callOncePerConnection calls fetch once for the connection and key, and
+// keeps its result in the connection cache until ttl has passed, or until
+// the connection's config changes and the SDK clears the cache. Concurrent
+// callers wait for the call in progress, which plugin.HydrateFunc.Memoize
+// does not guarantee. Errors are not cached, so the next caller tries again.
+func callOncePerConnection[T any](ctx context.Context, d *plugin.QueryData, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	lock, _ := connectionCallLocks.LoadOrStore(d.Connection.Name+"/"+key, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if cached, ok := d.ConnectionCache.Get(ctx, key); ok {
+		if value, ok := cached.(T); ok {
+			return value, nil
+		}
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	_ = d.ConnectionCache.SetWithTTL(ctx, key, value, ttl)
+	return value, nil
+}
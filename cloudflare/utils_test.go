@@ -0,0 +1,51 @@
+package cloudflare
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/cloudflare/cloudflare-go/v4"
+	"github.com/cloudflare/cloudflare-go/v4/zones"
+	"golang.org/x/time/rate"
+)
+
+func TestNewV4ClientRequestLimiter(t *testing.T) {
+	server := newFixtureServer(t, "zone_details")
+	config := cloudflareConfig{
+		Token:   aws.String("test-token"),
+		BaseURL: aws.String(server.URL + "/client/v4/"),
+	}
+
+	// The limiter refills too slowly to matter, so each request takes
+	// exactly one token
+	limiter := rate.NewLimiter(rate.Limit(0.001), 10)
+	client, err := newV4Client(config, limiter)
+	if err != nil {
+		t.Fatalf("newV4Client: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := client.Zones.Get(context.Background(), zones.ZoneGetParams{ZoneID: cloudflare.F("023e105f4ecef8ad9ca31a8372d0c353")}); err != nil {
+			t.Fatalf("get zone: %v", err)
+		}
+	}
+	if tokens := limiter.Tokens(); tokens < 6.9 || tokens > 7.1 {
+		t.Errorf("limiter has %.2f tokens left after 3 requests, want 7", tokens)
+	}
+}
+
+func TestGetRequestLimiter(t *testing.T) {
+	if limiter := getRequestLimiter(cloudflareConfig{}); limiter == nil || limiter.Limit() != 4 {
+		t.Errorf("default limiter = %v, want 4 requests per second", limiter)
+	}
+
+	t.Setenv("CLOUDFLARE_REQUESTS_PER_SECOND", "2.5")
+	if limiter := getRequestLimiter(cloudflareConfig{}); limiter == nil || limiter.Limit() != 2.5 || limiter.Burst() != 3 {
+		t.Errorf("limiter from environment = %v, want 2.5 requests per second with a burst of 3", limiter)
+	}
+
+	disabled := 0.0
+	if limiter := getRequestLimiter(cloudflareConfig{RequestsPerSecond: &disabled}); limiter != nil {
+		t.Errorf("limiter = %v, want nil when disabled", limiter)
+	}
+}
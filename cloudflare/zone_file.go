@@ -0,0 +1,254 @@
+package cloudflare
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// zoneFileRecord is a resource record parsed from a BIND zone file, with its
+// owner made absolute and its TTL and class filled in from the directives
+// and records before it.
+type zoneFileRecord struct {
+	Owner   string   `json:"owner"`
+	TTL     int64    `json:"ttl"`
+	Class   string   `json:"class"`
+	Type    string   `json:"type"`
+	Rdata   []string `json:"rdata"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// zoneFileLine is one logical line of a zone file. Parentheses continue a
+// line over several physical lines.
+type zoneFileLine struct {
+	number   int
+	indented bool
+	tokens   []string
+	comment  string
+}
+
+var zoneFileClasses = map[string]bool{"IN": true, "CH": true, "HS": true, "CS": true}
+
+// parseZoneFile parses the records in a BIND zone file. Owner names are
+// returned in lower case without the trailing dot, as in
+// cloudflare_dns_record, and rdata fields are kept as written, including the
+// quotes around character strings.
+func parseZoneFile(content string, origin string) ([]zoneFileRecord, error) {
+	lines, err := splitZoneFileLines(content)
+	if err != nil {
+		return nil, err
+	}
+
+	origin = strings.TrimSuffix(origin, ".") + "."
+	var records []zoneFileRecord
+	var defaultTTL, lastTTL int64
+	hasDefaultTTL := false
+	owner, class := origin, "IN"
+
+	for _, line := range lines {
+		tokens := line.tokens
+
+		if strings.HasPrefix(tokens[0], "$") && !line.indented {
+			directive := strings.ToUpper(tokens[0])
+			switch {
+			case directive == "$ORIGIN" && len(tokens) == 2:
+				origin = zoneFileAbsoluteName(tokens[1], origin)
+			case directive == "$TTL" && len(tokens) == 2:
+				ttl, ok := parseZoneFileTTL(tokens[1])
+				if !ok {
+					return nil, fmt.Errorf("zone file line %d: invalid TTL %q", line.number, tokens[1])
+				}
+				defaultTTL, hasDefaultTTL = ttl, true
+			default:
+				return nil, fmt.Errorf("zone file line %d: unsupported directive %s", line.number, tokens[0])
+			}
+			continue
+		}
+
+		// An indented record belongs to the previous owner
+		if !line.indented {
+			owner = zoneFileAbsoluteName(tokens[0], origin)
+			tokens = tokens[1:]
+		}
+
+		// The TTL and class may come in either order, and either may be left out
+		ttl, hasTTL := int64(0), false
+		for len(tokens) > 0 {
+			if value, ok := parseZoneFileTTL(tokens[0]); ok && !hasTTL {
+				ttl, hasTTL = value, true
+			} else if upper := strings.ToUpper(tokens[0]); zoneFileClasses[upper] {
+				class = upper
+			} else {
+				break
+			}
+			tokens = tokens[1:]
+		}
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("zone file line %d: missing record type", line.number)
+		}
+
+		switch {
+		case hasTTL:
+			lastTTL = ttl
+		case hasDefaultTTL:
+			ttl = defaultTTL
+		default:
+			ttl = lastTTL
+		}
+
+		records = append(records, zoneFileRecord{
+			Owner:   strings.ToLower(strings.TrimSuffix(owner, ".")),
+			TTL:     ttl,
+			Class:   class,
+			Type:    strings.ToUpper(tokens[0]),
+			Rdata:   append([]string{}, tokens[1:]...),
+			Comment: line.comment,
+		})
+	}
+
+	return records, nil
+}
+
+// splitZoneFileLines splits a zone file into logical lines of tokens,
+// dropping blank and comment-only lines.
+func splitZoneFileLines(content string) ([]zoneFileLine, error) {
+	var lines []zoneFileLine
+	var line zoneFileLine
+	var token strings.Builder
+	var comments []string
+	inToken, inQuote, depth := false, false, 0
+	number, lineStart := 1, true
+
+	endToken := func() {
+		if inToken {
+			line.tokens = append(line.tokens, token.String())
+			token.Reset()
+			inToken = false
+		}
+	}
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if lineStart && depth == 0 && !inQuote {
+			line = zoneFileLine{number: number, indented: r == ' ' || r == '\t'}
+			comments = nil
+		}
+		lineStart = false
+
+		switch {
+		case inQuote:
+			token.WriteRune(r)
+			if r == '\\' && i+1 < len(runes) {
+				i++
+				token.WriteRune(runes[i])
+			} else if r == '"' {
+				inQuote = false
+			} else if r == '\n' {
+				return nil, fmt.Errorf("zone file line %d: unterminated quoted string", number)
+			}
+		case r == '"':
+			inToken, inQuote = true, true
+			token.WriteRune(r)
+		case r == '\\' && i+1 < len(runes):
+			inToken = true
+			token.WriteRune(r)
+			i++
+			token.WriteRune(runes[i])
+		case r == ';':
+			endToken()
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			if comment := strings.TrimSpace(strings.TrimLeft(string(runes[i:end]), ";")); comment != "" {
+				comments = append(comments, comment)
+			}
+			i = end - 1
+		case r == '(':
+			endToken()
+			depth++
+		case r == ')':
+			endToken()
+			if depth == 0 {
+				return nil, fmt.Errorf("zone file line %d: unbalanced parentheses", number)
+			}
+			depth--
+		case r == '\n':
+			endToken()
+			number++
+			if depth == 0 {
+				if len(line.tokens) > 0 {
+					line.comment = strings.Join(comments, " ")
+					lines = append(lines, line)
+				}
+				lineStart = true
+			}
+		case r == ' ' || r == '\t' || r == '\r':
+			endToken()
+		default:
+			inToken = true
+			token.WriteRune(r)
+		}
+	}
+
+	if inQuote {
+		return nil, fmt.Errorf("zone file line %d: unterminated quoted string", number)
+	}
+	if depth > 0 {
+		return nil, fmt.Errorf("zone file line %d: unbalanced parentheses", number)
+	}
+	endToken()
+	if !lineStart && len(line.tokens) > 0 {
+		line.comment = strings.Join(comments, " ")
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// zoneFileAbsoluteName returns a name relative to origin as an absolute name
+// with a trailing dot. "@" stands for the origin itself. As in RFC 1035, only
+// a trailing dot makes a name absolute, so the origin is appended to any
+// other name, even one that already ends with the origin.
+func zoneFileAbsoluteName(name string, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return name
+	}
+	return name + "." + origin
+}
+
+// parseZoneFileTTL parses a TTL in seconds, or in BIND's units such as
+// "1h30m".
+func parseZoneFileTTL(value string) (int64, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return seconds, seconds >= 0
+	}
+
+	units := map[byte]int64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	var total, number int64
+	digits := false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= '0' && c <= '9':
+			number = number*10 + int64(c-'0')
+			digits = true
+		case digits && units[c|0x20] > 0:
+			total += number * units[c|0x20]
+			number, digits = 0, false
+		default:
+			return 0, false
+		}
+	}
+	if digits {
+		return 0, false
+	}
+	return total, true
+}
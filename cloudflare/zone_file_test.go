@@ -0,0 +1,92 @@
+package cloudflare
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseZoneFile(t *testing.T) {
+	content := `$TTL 300
+; a comment on its own line
+@ IN 3600 A 192.0.2.1
+  AAAA 2001:db8::1
+sub.Example.ORG. 1d CH TXT "a \"quoted\" ; string" "second"
+$ORIGIN deep.example.org.
+host MX ( 10
+	mail ) ; trailing
+`
+	records, err := parseZoneFile(content, "example.org")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []zoneFileRecord{
+		{Owner: "example.org", TTL: 3600, Class: "IN", Type: "A", Rdata: []string{"192.0.2.1"}},
+		{Owner: "example.org", TTL: 300, Class: "IN", Type: "AAAA", Rdata: []string{"2001:db8::1"}},
+		{Owner: "sub.example.org", TTL: 86400, Class: "CH", Type: "TXT", Rdata: []string{`"a \"quoted\" ; string"`, `"second"`}},
+		{Owner: "host.deep.example.org", TTL: 300, Class: "CH", Type: "MX", Rdata: []string{"10", "mail"}, Comment: "trailing"},
+	}
+	gotJSON, _ := json.Marshal(records)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("parseZoneFile() = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestZoneFileAbsoluteName(t *testing.T) {
+	tests := []struct {
+		name, origin, want string
+	}{
+		{"@", "example.com.", "example.com."},
+		{"www", "example.com.", "www.example.com."},
+		{"www.example.com.", "example.com.", "www.example.com."},
+		// Without a trailing dot a name is relative, even if it ends with
+		// the origin
+		{"example.com", "example.com.", "example.com.example.com."},
+		{"www.example.com", "example.com.", "www.example.com.example.com."},
+	}
+	for _, tt := range tests {
+		if got := zoneFileAbsoluteName(tt.name, tt.origin); got != tt.want {
+			t.Errorf("zoneFileAbsoluteName(%q, %q) = %q, want %q", tt.name, tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	tests := []struct {
+		name, content string
+	}{
+		{"unterminated quote", "@ TXT \"open\n"},
+		{"unbalanced parentheses", "@ SOA ( a b\n"},
+		{"missing type", "@ 300 IN\n"},
+		{"unsupported directive", "$INCLUDE other.zone\n"},
+		{"invalid default TTL", "$TTL forever\n"},
+	}
+	for _, tt := range tests {
+		if _, err := parseZoneFile(tt.content, "example.org"); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestParseZoneFileTTL(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+		ok    bool
+	}{
+		{"300", 300, true},
+		{"1h30m", 5400, true},
+		{"1W", 604800, true},
+		{"2d", 172800, true},
+		{"1h30", 0, false},
+		{"IN", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseZoneFileTTL(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseZoneFileTTL(%q) = %d, %v, want %d, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}